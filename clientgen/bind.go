@@ -0,0 +1,233 @@
+package clientgen
+
+import (
+	"go/types"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"golang.org/x/xerrors"
+)
+
+// Bind binds fragments by name and fields by schema coordinate such as User.posts to existing Go types,
+// given as package path.Name like models. The selection sets are decoded into the Go types instead of generated ones.
+type Bind map[string]string
+
+// Validate reports whether the Go types are given as package path.Name.
+func (b Bind) Validate() error {
+	keys := make([]string, 0, len(b))
+	for key := range b {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		goType := b[key]
+		if i := strings.LastIndex(goType, "."); i <= 0 || i == len(goType)-1 {
+			return xerrors.Errorf("%s: invalid Go type %q, must be package path.Name", key, goType)
+		}
+	}
+
+	return nil
+}
+
+// fragmentBinding returns the Go type the fragment is bound to, or nil if it is not bound.
+// The Go type is checked to hold the fields of the fragment the first time.
+func (r *SourceGenerator) fragmentBinding(fragment *ast.FragmentDefinition, responseFields ResponseFieldList) (types.Type, error) {
+	goTypeName, ok := r.bind[fragment.Name]
+	if !ok {
+		return nil, nil
+	}
+	if goType, ok := r.boundFragments[fragment.Name]; ok {
+		return goType, nil
+	}
+
+	coordinate := "fragment " + fragment.Name
+	goType, err := r.binder.FindTypeFromName(goTypeName)
+	if err != nil {
+		return nil, &SelectionError{
+			Position:   fragment.Position,
+			Coordinate: coordinate,
+			Err:        xerrors.Errorf("binding to %s: %w", goTypeName, err),
+		}
+	}
+	if err := checkBinding(goType, fragment.Position, coordinate, fragment.SelectionSet, responseFields); err != nil {
+		return nil, err
+	}
+	r.boundFragments[fragment.Name] = goType
+
+	return goType, nil
+}
+
+// checkBinding reports the first field of the selection set which the bound Go type can not hold,
+// as a SelectionError at the field. ResponseFields are the fields generated for the selection set,
+// and position and coordinate tell the selection set bound.
+func checkBinding(goType types.Type, position *ast.Position, coordinate string, selectionSet ast.SelectionSet, responseFields ResponseFieldList) error {
+	structType, ok := derefType(goType).Underlying().(*types.Struct)
+	if !ok {
+		return &SelectionError{
+			Position:   position,
+			Coordinate: coordinate,
+			Err:        xerrors.Errorf("%s is bound to a selection set but is not a struct", typeString(goType)),
+		}
+	}
+
+	for i, selection := range selectionSet {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			goField := structFieldByGraphQLName(structType, selection.Alias)
+			if goField == nil {
+				return &SelectionError{
+					Position:   selection.Position,
+					Coordinate: fieldCoordinate(selection),
+					Err:        xerrors.Errorf("%s has no field for %s", typeString(goType), selection.Alias),
+				}
+			}
+			if err := checkBoundField(goType, goField, selection, responseFields[i]); err != nil {
+				return err
+			}
+		case *ast.FragmentSpread:
+			if err := checkBinding(goType, position, coordinate, selection.Definition.SelectionSet, responseFields[i].ResponseFields); err != nil {
+				return err
+			}
+		case *ast.InlineFragment:
+			return &SelectionError{
+				Position:   selection.Position,
+				Coordinate: "... on " + selection.TypeCondition,
+				Err:        xerrors.Errorf("inline fragments can not be bound to %s", typeString(goType)),
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkBoundField reports whether the field of the bound Go type owner can hold the field of the response.
+// Pointers are ignored as null is decoded into the zero value, but lists must match,
+// and the fields of a selection set are checked against the struct in the Go field.
+func checkBoundField(owner types.Type, goField *types.Var, selection *ast.Field, responseField *ResponseField) error {
+	goType, generated := goField.Type(), responseField.Type
+	for {
+		goType, generated = derefType(goType), derefType(generated)
+		if decodable(goType, generated) {
+			return nil
+		}
+
+		generatedSlice, ok := generated.(*types.Slice)
+		if !ok {
+			break
+		}
+		goSlice, ok := goType.Underlying().(*types.Slice)
+		if !ok {
+			break
+		}
+		goType, generated = goSlice.Elem(), generatedSlice.Elem()
+	}
+
+	if len(selection.SelectionSet) > 0 {
+		if _, ok := generated.(*types.Slice); !ok {
+			if _, ok := goType.Underlying().(*types.Slice); !ok {
+				return checkBinding(goType, selection.Position, fieldCoordinate(selection), selection.SelectionSet, responseField.ResponseFields)
+			}
+		}
+	}
+
+	return &SelectionError{
+		Position:   selection.Position,
+		Coordinate: fieldCoordinate(selection),
+		Err: xerrors.Errorf("%s.%s is %s, which can not hold %s",
+			typeString(owner), goField.Name(), typeString(goField.Type()), typeString(responseField.Type)),
+	}
+}
+
+// decodable reports whether a value of the generated type can be decoded into the Go type:
+// the types are the same, they are both of the same basic type such as enums and strings, or the Go type unmarshals JSON.
+func decodable(goType, generated types.Type) bool {
+	if types.Identical(goType, generated) {
+		return true
+	}
+	if _, ok := goType.Underlying().(*types.Basic); ok && types.Identical(goType.Underlying(), generated.Underlying()) {
+		return true
+	}
+
+	return types.NewMethodSet(types.NewPointer(goType)).Lookup(nil, "UnmarshalJSON") != nil
+}
+
+// structFieldByGraphQLName returns the exported field of the struct which graphqljson decodes the field of the response into,
+// the field whose graphql tag has the name, or else whose name equals it ignoring case.
+// Fields promoted from embedded structs and fragments are found as encoding/json finds them, breadth first:
+// a shallower field hides deeper ones, and of fields at the same depth the one tagged wins, or none if they are ambiguous.
+func structFieldByGraphQLName(structType *types.Struct, name string) *types.Var {
+	visited := make(map[types.Type]bool)
+	for current := []*types.Struct{structType}; len(current) > 0; {
+		var next []*types.Struct
+		var found, tagged []*types.Var
+		for _, structType := range current {
+			if visited[structType] {
+				continue
+			}
+			visited[structType] = true
+
+			for i := 0; i < structType.NumFields(); i++ {
+				field := structType.Field(i)
+				tag, hasTag := reflect.StructTag(structType.Tag(i)).Lookup("graphql")
+				tag = strings.TrimSpace(tag)
+				if strings.HasPrefix(tag, "...") || field.Embedded() && !hasTag {
+					if embedded, ok := derefType(field.Type()).Underlying().(*types.Struct); ok {
+						next = append(next, embedded)
+					}
+
+					continue
+				}
+				if !field.Exported() {
+					continue
+				}
+
+				if !hasTag {
+					if strings.EqualFold(field.Name(), name) {
+						found = append(found, field)
+					}
+
+					continue
+				}
+				if i := strings.IndexAny(tag, "(:"); i != -1 {
+					tag = tag[:i]
+				}
+				if strings.TrimSpace(tag) == name {
+					found = append(found, field)
+					tagged = append(tagged, field)
+				}
+			}
+		}
+
+		switch {
+		case len(found) == 1:
+			return found[0]
+		case len(tagged) == 1:
+			return tagged[0]
+		case len(found) > 1:
+			return nil
+		}
+		current = next
+	}
+
+	return nil
+}
+
+func derefType(t types.Type) types.Type {
+	for {
+		pointer, ok := t.(*types.Pointer)
+		if !ok {
+			return t
+		}
+		t = pointer.Elem()
+	}
+}
+
+// typeString prints the type qualified by package names, as in domain.User.
+func typeString(t types.Type) string {
+	return types.TypeString(t, func(pkg *types.Package) string {
+		return pkg.Name()
+	})
+}
@@ -0,0 +1,72 @@
+package clientgen_test
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/Yamashou/gqlgenc/clientgen"
+)
+
+func TestStructFieldByGraphQLName(t *testing.T) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "domain.go", `package domain
+
+type Named struct {
+	Name     string
+	Nickname string
+	ID       string
+}
+
+type Tagged struct {
+	Title string `+"`graphql:\"name\"`"+`
+}
+
+type Other struct {
+	ID string
+}
+
+type User struct {
+	*Named
+	Other
+	Role  string
+	Posts Tagged `+"`graphql:\"... on User\"`"+`
+	Self  *User  `+"`graphql:\"... on User\"`"+`
+}
+`, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := new(types.Config).Check("domain", fset, []*ast.File{file}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := pkg.Scope().Lookup("User").Type().Underlying().(*types.Struct)
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "role", want: "Role"},
+		// promoted from the embedded struct
+		{name: "nickname", want: "Nickname"},
+		// the tagged field wins over the untagged one at the same depth
+		{name: "name", want: "Title"},
+		// ambiguous between the embedded structs, as in encoding/json
+		{name: "id", want: ""},
+		{name: "missing", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			if field := clientgen.StructFieldByGraphQLName(user, tt.name); field != nil {
+				got = field.Name()
+			}
+			if got != tt.want {
+				t.Errorf("got field %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
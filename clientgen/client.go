@@ -1,8 +1,11 @@
 package clientgen
 
 import (
+	"io/ioutil"
+
 	"github.com/99designs/gqlgen/codegen/config"
 	"github.com/99designs/gqlgen/plugin"
+	"github.com/vektah/gqlparser/v2/ast"
 	"golang.org/x/xerrors"
 )
 
@@ -11,6 +14,31 @@ var _ plugin.ConfigMutator = &Plugin{}
 type Plugin struct {
 	queryFilePaths []string
 	Client         config.PackageConfig
+	// SkipRootStructs skips the structs of the query and mutation types, which hold the root fields of the schema.
+	SkipRootStructs bool
+	// Split splits the client into a file for the operations of each query file with SplitByFile,
+	// or for each operation with SplitByOperation. The files are generated next to the client file.
+	Split string
+	// Template is the file of a template replacing the default one, executed with TemplateData.
+	Template string
+	// StructTags are the tags added to the fields of the responses besides json and graphql.
+	StructTags StructTags
+	// Bind binds fragments and fields to existing Go types instead of generating types for their selection sets.
+	Bind Bind
+	// OmitNilDefaults leaves nil variables which have a default value out of the request,
+	// so that the default applies instead of null.
+	OmitNilDefaults bool
+	// UsedTypes are the types the operations use, which models are generated for.
+	// When set, the root structs leave out the root fields of the other types, which have no models.
+	UsedTypes map[string]bool
+	// Document is the document of the operations and fragments which the client is generated for,
+	// as QueryDocument returns it. When nil, MutateConfig loads and parses the query files.
+	Document *ast.QueryDocument
+	// Hooks extend the steps of the generation.
+	Hooks []Hook
+	// Warn receives the warnings of the generation, such as the deprecated fields the operations select.
+	// They are dropped when nil.
+	Warn func(warning string)
 }
 
 func New(queryFilePaths []string, client config.PackageConfig) *Plugin {
@@ -24,38 +52,73 @@ func (p *Plugin) Name() string {
 	return "clientgen"
 }
 
-func (p *Plugin) MutateConfig(cfg *config.Config) error {
+// QueryDocument loads and parses the query files into a document of all the operations and fragments,
+// with the changes of the hooks.
+func (p *Plugin) QueryDocument(schema *ast.Schema) (*ast.QueryDocument, error) {
 	querySources, err := LoadQuerySources(p.queryFilePaths)
 	if err != nil {
-		return xerrors.Errorf("load query sources failed: %w", err)
+		return nil, xerrors.Errorf("load query sources failed: %w", err)
+	}
+	querySources, err = mutateQuerySources(p.Hooks, querySources)
+	if err != nil {
+		return nil, xerrors.Errorf("load query sources failed: %w", err)
+	}
+
+	queryDocument, err := ParseQueryDocuments(clientSchema(schema, p.clientDirectives()), querySources)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid query: %w", err)
+	}
+	if err := mutateQueryDocument(p.Hooks, queryDocument); err != nil {
+		return nil, xerrors.Errorf("invalid query: %w", err)
 	}
 
+	return queryDocument, nil
+}
+
+func (p *Plugin) MutateConfig(cfg *config.Config) error {
 	// 1. 全体のqueryDocumentを1度にparse
 	// 1. Parse document from source of query
-	queryDocument, err := ParseQueryDocuments(cfg.Schema, querySources)
-	if err != nil {
-		return xerrors.Errorf(": %w", err)
+	queryDocument := p.Document
+	if queryDocument == nil {
+		var err error
+		if queryDocument, err = p.QueryDocument(cfg.Schema); err != nil {
+			return err
+		}
+	}
+
+	if p.Warn != nil {
+		for _, warning := range DeprecatedFieldWarnings(queryDocument.Operations) {
+			p.Warn(warning)
+		}
 	}
 
 	// 2. OperationごとのqueryDocumentを作成
 	// 2. Separate documents for each operation
-	queryDocuments, err := QueryDocumentsByOperations(cfg.Schema, queryDocument.Operations)
+	queryDocuments, err := QueryDocumentsByOperations(clientSchema(cfg.Schema, p.clientDirectives()), queryDocument.Operations)
 	if err != nil {
 		return xerrors.Errorf("parse query document failed: %w", err)
 	}
 
 	// 3. テンプレートと情報ソースを元にコード生成
 	// 3. Generate code from template and document source
-	sourceGenerator := NewSourceGenerator(cfg, p.Client)
+	sourceGenerator := NewSourceGenerator(cfg, p.Client, p.Hooks...)
+	sourceGenerator.structTags = p.StructTags
+	sourceGenerator.bind = p.Bind
+	sourceGenerator.omitNilDefaults = p.OmitNilDefaults
+	sourceGenerator.usedTypes = p.UsedTypes
 	source := NewSource(cfg.Schema, queryDocument, sourceGenerator)
-	query, err := source.Query()
-	if err != nil {
-		return xerrors.Errorf("generating query object: %w", err)
-	}
+	var query *Query
+	var mutation *Mutation
+	if !p.SkipRootStructs {
+		query, err = source.Query()
+		if err != nil {
+			return xerrors.Errorf("generating query object: %w", err)
+		}
 
-	mutation, err := source.Mutation()
-	if err != nil {
-		return xerrors.Errorf("generating mutation object: %w", err)
+		mutation, err = source.Mutation()
+		if err != nil {
+			return xerrors.Errorf("generating mutation object: %w", err)
+		}
 	}
 
 	fragments, err := source.Fragments()
@@ -68,8 +131,64 @@ func (p *Plugin) MutateConfig(cfg *config.Config) error {
 		return xerrors.Errorf("generating operation response failed: %w", err)
 	}
 
-	if err := RenderTemplate(cfg, query, mutation, fragments, source.Operations(queryDocuments), operationResponses, p.Client); err != nil {
-		return xerrors.Errorf("template failed: %w", err)
+	namedTypes, err := source.NamedTypes()
+	if err != nil {
+		return xerrors.Errorf("generating named type failed: %w", err)
+	}
+
+	operations, err := source.Operations(queryDocuments)
+	if err != nil {
+		return xerrors.Errorf("generating operation failed: %w", err)
+	}
+	for _, operation := range operations {
+		if err := mutateOperation(p.Hooks, operation); err != nil {
+			return xerrors.Errorf("generating operation failed: %w", err)
+		}
+	}
+
+	var tmpl string
+	if p.Template != "" {
+		b, err := ioutil.ReadFile(p.Template)
+		if err != nil {
+			return xerrors.Errorf("template failed: %w", err)
+		}
+		tmpl = string(b)
+	}
+
+	var files []*splitFile
+	if p.Split == "" {
+		if err := render(cfg, p.Client, tmpl, p.Client.Filename, &TemplateData{
+			Client:            true,
+			Query:             query,
+			Mutation:          mutation,
+			Fragment:          fragments,
+			NamedType:         namedTypes,
+			Operation:         operations,
+			OperationResponse: operationResponses,
+			Schema:            cfg.Schema,
+		}); err != nil {
+			return xerrors.Errorf("template failed: %w", err)
+		}
+	} else {
+		files, err = splitFiles(p.Split, p.Client, queryDocument, fragments, namedTypes, operations, operationResponses)
+		if err != nil {
+			return xerrors.Errorf("split failed: %w", err)
+		}
+		if err := renderSplit(cfg, query, mutation, files, p.Client, tmpl); err != nil {
+			return xerrors.Errorf("template failed: %w", err)
+		}
+	}
+
+	if err := removeStaleFiles(p.Client.Filename, files); err != nil {
+		return xerrors.Errorf("split failed: %w", err)
+	}
+
+	filenames := []string{p.Client.Filename}
+	for _, file := range files {
+		filenames = append(filenames, file.Filename)
+	}
+	if err := afterRender(p.Hooks, filenames); err != nil {
+		return xerrors.Errorf("after render failed: %w", err)
 	}
 
 	return nil
@@ -0,0 +1,96 @@
+package clientgen
+
+import (
+	"go/types"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// OmitWhenNil reports whether the variable is left out of the request when it is nil,
+// so that the default value applies instead of null, which is only done with OmitNilDefaults.
+func (a *Argument) OmitWhenNil() bool {
+	if !a.omitNilDefault || a.DefaultValue == nil {
+		return false
+	}
+	_, ok := a.Type.(*types.Pointer)
+
+	return ok
+}
+
+// schemaInput is what the schema defines about the arguments and input fields a variable is passed to.
+type schemaInput struct {
+	DefaultValue *ast.Value
+	Description  string
+}
+
+// schemaInputs returns the default values and descriptions of the arguments and input fields
+// which the variables of the operation are passed to, by variable name.
+func schemaInputs(operation *ast.OperationDefinition) map[string]*schemaInput {
+	inputs := make(map[string]*schemaInput)
+	walkSelectionSetInputs(operation.SelectionSet, inputs)
+
+	return inputs
+}
+
+func walkSelectionSetInputs(selectionSet ast.SelectionSet, inputs map[string]*schemaInput) {
+	for _, selection := range selectionSet {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if selection.Definition != nil {
+				walkArgumentInputs(selection.Arguments, selection.Definition.Arguments, inputs)
+			}
+			walkSelectionSetInputs(selection.SelectionSet, inputs)
+		case *ast.FragmentSpread:
+			if selection.Definition != nil {
+				walkSelectionSetInputs(selection.Definition.SelectionSet, inputs)
+			}
+		case *ast.InlineFragment:
+			walkSelectionSetInputs(selection.SelectionSet, inputs)
+		}
+	}
+}
+
+func walkArgumentInputs(arguments ast.ArgumentList, definitions ast.ArgumentDefinitionList, inputs map[string]*schemaInput) {
+	for _, argument := range arguments {
+		definition := definitions.ForName(argument.Name)
+		if definition == nil {
+			continue
+		}
+		walkValueInputs(argument.Value, definition.DefaultValue, definition.Description, inputs)
+	}
+}
+
+func walkValueInputs(value, defaultValue *ast.Value, description string, inputs map[string]*schemaInput) {
+	if value == nil {
+		return
+	}
+
+	switch value.Kind {
+	case ast.Variable:
+		input := inputs[value.Raw]
+		if input == nil {
+			input = &schemaInput{}
+			inputs[value.Raw] = input
+		}
+		if input.DefaultValue == nil {
+			input.DefaultValue = defaultValue
+		}
+		if input.Description == "" {
+			input.Description = description
+		}
+	case ast.ObjectValue:
+		if value.Definition == nil {
+			return
+		}
+		for _, child := range value.Children {
+			if field := value.Definition.Fields.ForName(child.Name); field != nil {
+				walkValueInputs(child.Value, field.DefaultValue, field.Description, inputs)
+			}
+		}
+	case ast.ListValue:
+		// the elements share the description of the list, but not its default value
+		for _, child := range value.Children {
+			walkValueInputs(child.Value, nil, description, inputs)
+		}
+	}
+}
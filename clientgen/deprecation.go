@@ -0,0 +1,74 @@
+package clientgen
+
+import (
+	"fmt"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// defaultDeprecationReason is the default value of the reason argument of @deprecated.
+const defaultDeprecationReason = "No longer supported"
+
+// deprecation returns whether the directives of a field definition include @deprecated, and its reason.
+func deprecation(directives ast.DirectiveList) (bool, string) {
+	directive := directives.ForName("deprecated")
+	if directive == nil {
+		return false, ""
+	}
+
+	if reason := directive.Arguments.ForName("reason"); reason != nil && reason.Value != nil {
+		return true, reason.Value.Raw
+	}
+
+	return true, defaultDeprecationReason
+}
+
+// DeprecatedFieldWarnings returns a warning for every deprecated field selected by the operations,
+// including through fragments.
+func DeprecatedFieldWarnings(operations ast.OperationList) []string {
+	var warnings []string
+	for _, operation := range operations {
+		for _, field := range deprecatedFields(operation.SelectionSet, map[string]bool{}) {
+			_, reason := deprecation(field.Definition.Directives)
+			warnings = append(warnings, fmt.Sprintf(
+				"%s: %s selects deprecated field %s.%s: %s",
+				positionString(field.Position), operation.Name, field.ObjectDefinition.Name, field.Name, reason,
+			))
+		}
+	}
+
+	return warnings
+}
+
+func deprecatedFields(selectionSet ast.SelectionSet, visitedFragments map[string]bool) []*ast.Field {
+	var fields []*ast.Field
+	for _, selection := range selectionSet {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if selection.Definition != nil && selection.ObjectDefinition != nil {
+				if isDeprecated, _ := deprecation(selection.Definition.Directives); isDeprecated {
+					fields = append(fields, selection)
+				}
+			}
+			fields = append(fields, deprecatedFields(selection.SelectionSet, visitedFragments)...)
+		case *ast.InlineFragment:
+			fields = append(fields, deprecatedFields(selection.SelectionSet, visitedFragments)...)
+		case *ast.FragmentSpread:
+			if selection.Definition == nil || visitedFragments[selection.Name] {
+				continue
+			}
+			visitedFragments[selection.Name] = true
+			fields = append(fields, deprecatedFields(selection.Definition.SelectionSet, visitedFragments)...)
+		}
+	}
+
+	return fields
+}
+
+func positionString(position *ast.Position) string {
+	if position == nil || position.Src == nil {
+		return "unknown"
+	}
+
+	return fmt.Sprintf("%s:%d:%d", position.Src.Name, position.Line, position.Column)
+}
@@ -0,0 +1,38 @@
+package clientgen_test
+
+import (
+	"testing"
+
+	"github.com/Yamashou/gqlgenc/clientgen"
+	"github.com/google/go-cmp/cmp"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestDeprecatedFieldWarnings(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+type Query { user: User }
+type User {
+  name: String
+  nickname: String @deprecated(reason: "use name")
+  age: Int @deprecated
+}
+`})
+	query, err := clientgen.ParseQueryDocuments(schema, []*ast.Source{{Name: "query.graphql", Input: `
+query GetUser { user { name ...UserAge } }
+query GetNickname { user { nickname } }
+fragment UserAge on User { age }
+`}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"query.graphql:4:28: GetUser selects deprecated field User.age: No longer supported",
+		"query.graphql:3:28: GetNickname selects deprecated field User.nickname: use name",
+	}
+	got := clientgen.DeprecatedFieldWarnings(query.Operations)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,148 @@
+package clientgen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"golang.org/x/xerrors"
+)
+
+// Diagnostic is an error in a query file.
+type Diagnostic struct {
+	File    string `json:"file"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Message string `json:"message"`
+	// Rule is the name of the validation rule, empty for syntax errors
+	Rule string `json:"rule,omitempty"`
+
+	// excerpt is the line of the query file at Line
+	excerpt string
+}
+
+// String returns the diagnostic as file:line:col: message, followed by the line of the query file
+// and a caret under the column.
+func (d *Diagnostic) String() string {
+	var b strings.Builder
+	b.WriteString(d.File)
+	if d.Line > 0 {
+		fmt.Fprintf(&b, ":%d", d.Line)
+		if d.Column > 0 {
+			fmt.Fprintf(&b, ":%d", d.Column)
+		}
+	}
+	fmt.Fprintf(&b, ": %s", d.Message)
+
+	if d.excerpt == "" {
+		return b.String()
+	}
+
+	gutter := fmt.Sprint(d.Line)
+	fmt.Fprintf(&b, "\n %s | %s", gutter, d.excerpt)
+	// columns count runes rather than bytes
+	if excerpt := []rune(d.excerpt); d.Column > 0 && d.Column <= len(excerpt)+1 {
+		// keep tabs so that the caret lines up with the excerpt
+		indent := strings.Map(func(r rune) rune {
+			if r == '\t' {
+				return r
+			}
+
+			return ' '
+		}, string(excerpt[:d.Column-1]))
+		fmt.Fprintf(&b, "\n %s | %s^", strings.Repeat(" ", len(gutter)), indent)
+	}
+
+	return b.String()
+}
+
+// Diagnostics are all the errors found in the query files.
+type Diagnostics []*Diagnostic
+
+func (ds Diagnostics) Error() string {
+	diagnostics := make([]string, 0, len(ds))
+	for _, d := range ds {
+		diagnostics = append(diagnostics, d.String())
+	}
+
+	return strings.Join(diagnostics, "\n")
+}
+
+func newDiagnostics(errs gqlerror.List, sources map[string]*ast.Source) Diagnostics {
+	diagnostics := make(Diagnostics, 0, len(errs))
+	for _, err := range errs {
+		diagnostics = append(diagnostics, newDiagnostic(err, sources))
+	}
+
+	return diagnostics
+}
+
+func newDiagnostic(err *gqlerror.Error, sources map[string]*ast.Source) *Diagnostic {
+	file, _ := err.Extensions["file"].(string)
+	d := &Diagnostic{
+		File:    file,
+		Message: err.Message,
+		Rule:    err.Rule,
+	}
+	if len(err.Locations) > 0 {
+		d.Line = err.Locations[0].Line
+		d.Column = err.Locations[0].Column
+	}
+
+	d.excerpt = excerpt(sources[file], d.Line)
+
+	return d
+}
+
+// ErrorDiagnostics returns the diagnostics the error holds: those of the query files,
+// or else the diagnostic of a SelectionError at its position, or of a MissingModelError.
+// It reports false if the error holds none of them.
+func ErrorDiagnostics(err error) (Diagnostics, bool) {
+	var diagnostics Diagnostics
+	if xerrors.As(err, &diagnostics) {
+		return diagnostics, true
+	}
+
+	var selectionErr *SelectionError
+	if xerrors.As(err, &selectionErr) {
+		d := &Diagnostic{Message: fmt.Sprintf("%s: %v", selectionErr.Coordinate, selectionErr.Err)}
+		if position := selectionErr.Position; position != nil && position.Src != nil {
+			d.File = position.Src.Name
+			d.Line = position.Line
+			d.Column = position.Column
+			d.excerpt = excerpt(position.Src, position.Line)
+		}
+
+		return Diagnostics{d}, true
+	}
+
+	var missingModelErr *MissingModelError
+	if xerrors.As(err, &missingModelErr) {
+		return Diagnostics{{Message: missingModelErr.Error()}}, true
+	}
+
+	return nil, false
+}
+
+// excerpt returns the line of the source, empty if it is unknown.
+func excerpt(source *ast.Source, line int) string {
+	if source == nil || line <= 0 {
+		return ""
+	}
+	lines := strings.Split(source.Input, "\n")
+	if line > len(lines) {
+		return ""
+	}
+
+	return strings.TrimRight(lines[line-1], "\r")
+}
+
+func sourceMap(sources []*ast.Source) map[string]*ast.Source {
+	sourceMap := make(map[string]*ast.Source, len(sources))
+	for _, source := range sources {
+		sourceMap[source.Name] = source
+	}
+
+	return sourceMap
+}
@@ -0,0 +1,120 @@
+package clientgen_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Yamashou/gqlgenc/clientgen"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"golang.org/x/xerrors"
+)
+
+func TestParseQueryDocuments_diagnostics(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+type Query { user: User }
+type User { name: String }
+`})
+
+	tests := []struct {
+		name    string
+		sources []*ast.Source
+		want    string
+	}{
+		{
+			name: "syntax errors of all files",
+			sources: []*ast.Source{
+				{Name: "a.graphql", Input: "query A {\n  user {\n"},
+				{Name: "b.graphql", Input: "query B { user { name } }"},
+				{Name: "c.graphql", Input: "query C {\n\tuser(: 1)\n}"},
+			},
+			want: `a.graphql:3:1: Expected Name, found <EOF>
+c.graphql:2:7: Expected Name, found :
+ 2 | 	user(: 1)
+   | 	     ^`,
+		},
+		{
+			name: "all validation errors",
+			sources: []*ast.Source{
+				{Name: "query.graphql", Input: "query A {\n  user { age }\n}\nquery B {\n  users\n}\n"},
+			},
+			want: `query.graphql:2:10: Cannot query field "age" on type "User". Did you mean "name"?
+ 2 |   user { age }
+   |          ^
+query.graphql:5:3: Cannot query field "users" on type "Query". Did you mean "user"?
+ 5 |   users
+   |   ^`,
+		},
+		{
+			name: "caret after multibyte characters",
+			sources: []*ast.Source{
+				{Name: "query.graphql", Input: `query A($s: String = "日本語") { user { age } }`},
+			},
+			want: `query.graphql:1:38: Cannot query field "age" on type "User". Did you mean "name"?
+ 1 | query A($s: String = "日本語") { user { age } }
+   |                                      ^
+query.graphql:1:9: Variable "$s" is never used in operation "A".
+ 1 | query A($s: String = "日本語") { user { age } }
+   |         ^`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := clientgen.ParseQueryDocuments(schema, tt.sources)
+			var diagnostics clientgen.Diagnostics
+			if !errors.As(err, &diagnostics) {
+				t.Fatalf("want Diagnostics, got %v", err)
+			}
+			if diff := cmp.Diff(tt.want, diagnostics.Error()); diff != "" {
+				t.Errorf("diagnostics mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestErrorDiagnostics(t *testing.T) {
+	source := &ast.Source{Name: "query.graphql", Input: "query A {\n  user { name }\n}"}
+
+	tests := []struct {
+		name string
+		err  error
+		want clientgen.Diagnostics
+	}{
+		{
+			name: "selection error",
+			err: xerrors.Errorf("generating failed: %w", &clientgen.SelectionError{
+				Position:   &ast.Position{Src: source, Line: 2, Column: 10},
+				Coordinate: "User.name",
+				Err:        &clientgen.MissingModelError{TypeName: "Name"},
+			}),
+			want: clientgen.Diagnostics{{
+				File:    "query.graphql",
+				Line:    2,
+				Column:  10,
+				Message: "User.name: no Go type is bound to Name, add it to models",
+			}},
+		},
+		{
+			name: "missing model without position",
+			err:  xerrors.Errorf("generating failed: %w", &clientgen.MissingModelError{TypeName: "Name"}),
+			want: clientgen.Diagnostics{{Message: "no Go type is bound to Name, add it to models"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := clientgen.ErrorDiagnostics(tt.err)
+			if !ok {
+				t.Fatalf("want diagnostics, got %v", tt.err)
+			}
+			if diff := cmp.Diff(tt.want, got, cmpopts.IgnoreUnexported(clientgen.Diagnostic{})); diff != "" {
+				t.Errorf("diagnostics mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	if _, ok := clientgen.ErrorDiagnostics(xerrors.New("failed")); ok {
+		t.Error("want no diagnostics of an error without position")
+	}
+}
@@ -0,0 +1,188 @@
+package clientgen
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+	"golang.org/x/xerrors"
+)
+
+// The client directives of fields besides goTagDirective.
+const (
+	// goTypeDirective names the type generated for the selection set of the field, as in @goType(name: "User").
+	goTypeDirective = "goType"
+	// goFieldDirective renames the Go field of the field and binds it to an existing Go type,
+	// as in @goField(name: "ID", type: "github.com/google/uuid.UUID").
+	goFieldDirective = "goField"
+	// nonnullDirective generates the field of a nullable type as if it were non null, for fields which are never null in practice.
+	nonnullDirective = "nonnull"
+)
+
+// clientDirectiveNames are the directives of query files which gqlgenc interprets,
+// and removes from the queries it sends since the server does not know them.
+var clientDirectiveNames = map[string]bool{
+	goTagDirective:   true,
+	goTypeDirective:  true,
+	goFieldDirective: true,
+	nonnullDirective: true,
+}
+
+// clientDirectives returns the definitions of the client directives.
+func (p *Plugin) clientDirectives() []*ast.DirectiveDefinition {
+	return []*ast.DirectiveDefinition{
+		p.StructTags.goTagDefinition(),
+		{
+			Description: "Names the type generated for the selection set of the field.",
+			Name:        goTypeDirective,
+			Arguments: ast.ArgumentDefinitionList{
+				{Name: "name", Type: ast.NonNullNamedType("String", nil)},
+			},
+			Locations: []ast.DirectiveLocation{ast.LocationField},
+		},
+		{
+			Description: "Renames the Go field of the field, and binds it to an existing Go type as package path.Name.",
+			Name:        goFieldDirective,
+			Arguments: ast.ArgumentDefinitionList{
+				{Name: "name", Type: ast.NamedType("String", nil)},
+				{Name: "type", Type: ast.NamedType("String", nil)},
+			},
+			Locations: []ast.DirectiveLocation{ast.LocationField},
+		},
+		{
+			Description: "Generates the field as non null although it is nullable in the schema.",
+			Name:        nonnullDirective,
+			Locations:   []ast.DirectiveLocation{ast.LocationField},
+		},
+	}
+}
+
+// fieldDirectives are the client directives of a field but @goTag, which StructTags handles.
+type fieldDirectives struct {
+	// TypeName is the name of @goType
+	TypeName string
+	// FieldName and Type are the name and the type of @goField
+	FieldName string
+	Type      string
+	// NonNull is whether there is @nonnull
+	NonNull bool
+}
+
+func newFieldDirectives(directives ast.DirectiveList) (*fieldDirectives, error) {
+	var d fieldDirectives
+	var err error
+	if directive := directives.ForName(goTypeDirective); directive != nil {
+		if d.TypeName, err = constantString(directive, "name"); err != nil {
+			return nil, err
+		}
+	}
+	if directive := directives.ForName(goFieldDirective); directive != nil {
+		if d.FieldName, err = constantString(directive, "name"); err != nil {
+			return nil, err
+		}
+		if d.Type, err = constantString(directive, "type"); err != nil {
+			return nil, err
+		}
+	}
+	d.NonNull = directives.ForName(nonnullDirective) != nil
+
+	return &d, nil
+}
+
+// constantValue returns the value of the argument of the directive, which must not be a variable
+// since the code is generated before any variable is known.
+func constantValue(directive *ast.Directive, argument *ast.Argument) (interface{}, error) {
+	value, err := argument.Value.Value(nil)
+	if err != nil || argument.Value.Kind == ast.Variable {
+		return nil, xerrors.Errorf("@%s(%s:) must be a constant", directive.Name, argument.Name)
+	}
+
+	return value, nil
+}
+
+// constantString returns the string argument of the directive, or "" if it is not given.
+func constantString(directive *ast.Directive, name string) (string, error) {
+	argument := directive.Arguments.ForName(name)
+	if argument == nil {
+		return "", nil
+	}
+	value, err := constantValue(directive, argument)
+	if err != nil {
+		return "", err
+	}
+	s, _ := value.(string)
+
+	return s, nil
+}
+
+// clientSchema returns a copy of the schema which also defines the client directives,
+// so that the query files using them are valid. The schema itself is left as is, since it is written to the schema lock.
+func clientSchema(schema *ast.Schema, directives []*ast.DirectiveDefinition) *ast.Schema {
+	clientSchema := *schema
+	clientSchema.Directives = make(map[string]*ast.DirectiveDefinition, len(schema.Directives)+len(directives))
+	for name, directive := range schema.Directives {
+		clientSchema.Directives[name] = directive
+	}
+	for _, directive := range directives {
+		clientSchema.Directives[directive.Name] = directive
+	}
+
+	return &clientSchema
+}
+
+// withoutClientDirectives returns a copy of the document without the client directives.
+func withoutClientDirectives(queryDocument *ast.QueryDocument) *ast.QueryDocument {
+	document := &ast.QueryDocument{Position: queryDocument.Position}
+	for _, operation := range queryDocument.Operations {
+		copied := *operation
+		copied.Directives = withoutClientDirectiveList(operation.Directives)
+		copied.SelectionSet = withoutClientDirectiveSelections(operation.SelectionSet)
+		document.Operations = append(document.Operations, &copied)
+	}
+	for _, fragment := range queryDocument.Fragments {
+		copied := *fragment
+		copied.Directives = withoutClientDirectiveList(fragment.Directives)
+		copied.SelectionSet = withoutClientDirectiveSelections(fragment.SelectionSet)
+		document.Fragments = append(document.Fragments, &copied)
+	}
+
+	return document
+}
+
+func withoutClientDirectiveSelections(selectionSet ast.SelectionSet) ast.SelectionSet {
+	if selectionSet == nil {
+		return nil
+	}
+
+	selections := make(ast.SelectionSet, 0, len(selectionSet))
+	for _, selection := range selectionSet {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			copied := *selection
+			copied.Directives = withoutClientDirectiveList(selection.Directives)
+			copied.SelectionSet = withoutClientDirectiveSelections(selection.SelectionSet)
+			selections = append(selections, &copied)
+		case *ast.FragmentSpread:
+			copied := *selection
+			copied.Directives = withoutClientDirectiveList(selection.Directives)
+			selections = append(selections, &copied)
+		case *ast.InlineFragment:
+			copied := *selection
+			copied.Directives = withoutClientDirectiveList(selection.Directives)
+			copied.SelectionSet = withoutClientDirectiveSelections(selection.SelectionSet)
+			selections = append(selections, &copied)
+		default:
+			selections = append(selections, selection)
+		}
+	}
+
+	return selections
+}
+
+func withoutClientDirectiveList(directives ast.DirectiveList) ast.DirectiveList {
+	var kept ast.DirectiveList
+	for _, directive := range directives {
+		if !clientDirectiveNames[directive.Name] {
+			kept = append(kept, directive)
+		}
+	}
+
+	return kept
+}
@@ -0,0 +1,79 @@
+package clientgen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/99designs/gqlgen/codegen/templates"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Doc returns the doc comment of the method sending the operation: the comment preceding the operation
+// in the query file, and the description and default value of each variable.
+func (o *Operation) Doc() string {
+	summary := fmt.Sprintf("%s sends %sQuery.", templates.ToGo(o.Name), templates.ToGo(o.Name))
+	if o.Description != "" {
+		// in a paragraph of its own, gofmt would turn a short comment such as "Fetches a user" into a heading
+		summary += "\n" + o.Description
+	}
+	paragraphs := []string{summary}
+
+	var args []string
+	for _, arg := range o.Args {
+		name := templates.ToGoPrivate(arg.Variable)
+		var defaults string
+		if arg.DefaultValue != nil {
+			defaults = fmt.Sprintf("defaults to %s", arg.DefaultValue.String())
+			if arg.OmitWhenNil() {
+				defaults += " when nil"
+			}
+		}
+
+		switch {
+		case arg.Description != "" && defaults != "":
+			args = append(args, fmt.Sprintf("%s: %s\n%s %s.", name, arg.Description, name, defaults))
+		case arg.Description != "":
+			args = append(args, fmt.Sprintf("%s: %s", name, arg.Description))
+		case defaults != "":
+			args = append(args, fmt.Sprintf("%s %s.", name, defaults))
+		}
+	}
+	if len(args) > 0 {
+		paragraphs = append(paragraphs, strings.Join(args, "\n"))
+	}
+
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// leadingComment returns the # comment lines immediately preceding the position in its source.
+func leadingComment(position *ast.Position) string {
+	if position == nil || position.Src == nil {
+		return ""
+	}
+
+	lines := strings.Split(position.Src.Input, "\n")
+	var comment []string
+	for i := position.Line - 2; i >= 0 && i < len(lines); i-- {
+		line := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(line, "#") {
+			break
+		}
+		comment = append([]string{strings.TrimPrefix(strings.TrimPrefix(line, "#"), " ")}, comment...)
+	}
+
+	return strings.Join(comment, "\n")
+}
+
+// comment prints the text as a // comment, with an empty comment line for every blank line.
+func comment(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = "//"
+		} else {
+			lines[i] = "// " + line
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
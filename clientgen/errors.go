@@ -0,0 +1,41 @@
+package clientgen
+
+import (
+	"fmt"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// SelectionError is an error generating the code for a selection or a variable of a query file.
+type SelectionError struct {
+	Position *ast.Position
+	// Coordinate is the schema coordinate of the selected field such as User.name,
+	// or the variable such as $id.
+	Coordinate string
+	Err        error
+}
+
+func (e *SelectionError) Error() string {
+	return fmt.Sprintf("%s: %s: %v", positionString(e.Position), e.Coordinate, e.Err)
+}
+
+func (e *SelectionError) Unwrap() error {
+	return e.Err
+}
+
+// MissingModelError is returned when no Go type is bound to a schema type.
+type MissingModelError struct {
+	TypeName string
+}
+
+func (e *MissingModelError) Error() string {
+	return fmt.Sprintf("no Go type is bound to %s, add it to models", e.TypeName)
+}
+
+func fieldCoordinate(field *ast.Field) string {
+	if field.ObjectDefinition == nil {
+		return field.Name
+	}
+
+	return fmt.Sprintf("%s.%s", field.ObjectDefinition.Name, field.Name)
+}
@@ -0,0 +1,3 @@
+package clientgen
+
+var StructFieldByGraphQLName = structFieldByGraphQLName
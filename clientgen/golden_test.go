@@ -0,0 +1,22 @@
+package clientgen_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/Yamashou/gqlgenc/internal/clientgentest"
+)
+
+func TestGolden(t *testing.T) {
+	dirs, err := filepath.Glob("testdata/golden/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, dir := range dirs {
+		dir := dir
+		t.Run(filepath.Base(dir), func(t *testing.T) {
+			clientgentest.Run(t, dir)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package clientgen
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+	"golang.org/x/xerrors"
+)
+
+// Hook extends the steps of generating the client. Besides Name, a hook implements the interfaces
+// of the steps it extends: QuerySourcesMutator, QueryDocumentMutator, ResponseFieldMutator,
+// OperationMutator and AfterRenderer.
+type Hook interface {
+	Name() string
+}
+
+// QuerySourcesMutator changes the query files after they are loaded and before they are parsed.
+type QuerySourcesMutator interface {
+	MutateQuerySources(sources []*ast.Source) ([]*ast.Source, error)
+}
+
+// QueryDocumentMutator changes the document of all the operations and fragments after it is validated.
+type QueryDocumentMutator interface {
+	MutateQueryDocument(queryDocument *ast.QueryDocument) error
+}
+
+// ResponseFieldMutator changes the field of a response struct built from the field definition,
+// for example to add struct tags or to change its type.
+// The fields of fragment spreads and inline fragments have no definition and are not passed.
+type ResponseFieldMutator interface {
+	MutateResponseField(field *ResponseField, definition *ast.FieldDefinition) error
+}
+
+// OperationMutator changes the operation which the method of the client is generated for.
+type OperationMutator interface {
+	MutateOperation(operation *Operation) error
+}
+
+// AfterRenderer is called with the files of the client after they are written.
+type AfterRenderer interface {
+	AfterRender(filenames []string) error
+}
+
+func mutateQuerySources(hooks []Hook, sources []*ast.Source) ([]*ast.Source, error) {
+	for _, hook := range hooks {
+		if mutator, ok := hook.(QuerySourcesMutator); ok {
+			var err error
+			sources, err = mutator.MutateQuerySources(sources)
+			if err != nil {
+				return nil, xerrors.Errorf("%s: %w", hook.Name(), err)
+			}
+		}
+	}
+
+	return sources, nil
+}
+
+func mutateQueryDocument(hooks []Hook, queryDocument *ast.QueryDocument) error {
+	for _, hook := range hooks {
+		if mutator, ok := hook.(QueryDocumentMutator); ok {
+			if err := mutator.MutateQueryDocument(queryDocument); err != nil {
+				return xerrors.Errorf("%s: %w", hook.Name(), err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func mutateResponseField(hooks []Hook, field *ResponseField, definition *ast.FieldDefinition) error {
+	for _, hook := range hooks {
+		if mutator, ok := hook.(ResponseFieldMutator); ok {
+			if err := mutator.MutateResponseField(field, definition); err != nil {
+				return xerrors.Errorf("%s: %w", hook.Name(), err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func mutateOperation(hooks []Hook, operation *Operation) error {
+	for _, hook := range hooks {
+		if mutator, ok := hook.(OperationMutator); ok {
+			if err := mutator.MutateOperation(operation); err != nil {
+				return xerrors.Errorf("%s: %w", hook.Name(), err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func afterRender(hooks []Hook, filenames []string) error {
+	for _, hook := range hooks {
+		if renderer, ok := hook.(AfterRenderer); ok {
+			if err := renderer.AfterRender(filenames); err != nil {
+				return xerrors.Errorf("%s: %w", hook.Name(), err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package clientgen_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/codegen/config"
+	"github.com/Yamashou/gqlgenc/clientgen"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+type queryHook struct {
+	source     *ast.Source
+	operations []string
+	err        error
+}
+
+func (h *queryHook) Name() string {
+	return "queryHook"
+}
+
+func (h *queryHook) MutateQuerySources(sources []*ast.Source) ([]*ast.Source, error) {
+	if h.err != nil {
+		return nil, h.err
+	}
+
+	return append(sources, h.source), nil
+}
+
+func (h *queryHook) MutateQueryDocument(queryDocument *ast.QueryDocument) error {
+	for _, operation := range queryDocument.Operations {
+		h.operations = append(h.operations, operation.Name)
+	}
+
+	return nil
+}
+
+func TestPlugin_QueryDocument_hooks(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: `type Query { name: String }`})
+	hook := &queryHook{source: &ast.Source{Name: "generated.graphql", Input: `query Name { name }`}}
+	p := clientgen.New(nil, config.PackageConfig{})
+	p.Hooks = []clientgen.Hook{hook}
+
+	queryDocument, err := p.QueryDocument(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(queryDocument.Operations) != 1 || queryDocument.Operations[0].Name != "Name" {
+		t.Errorf("want the operation of the added source, got %+v", queryDocument.Operations)
+	}
+	if len(hook.operations) != 1 || hook.operations[0] != "Name" {
+		t.Errorf("want the document passed to the hook, got %v", hook.operations)
+	}
+
+	hook.err = errors.New("broken")
+	_, err = p.QueryDocument(schema)
+	if err == nil || !strings.Contains(err.Error(), "queryHook: broken") {
+		t.Errorf("want the error of the hook, got %v", err)
+	}
+}
@@ -2,24 +2,32 @@ package clientgen
 
 import (
 	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
 	"github.com/vektah/gqlparser/v2/parser"
 	"github.com/vektah/gqlparser/v2/validator"
-	"golang.org/x/xerrors"
 )
 
+// ParseQueryDocuments parses and validates the query files. The error is Diagnostics holding every error found.
 func ParseQueryDocuments(schema *ast.Schema, querySources []*ast.Source) (*ast.QueryDocument, error) {
 	var queryDocument ast.QueryDocument
+	var errs gqlerror.List
 	for _, querySource := range querySources {
 		query, gqlerr := parser.ParseQuery(querySource)
 		if gqlerr != nil {
-			return nil, xerrors.Errorf(": %w", gqlerr)
+			// keep parsing to report the syntax errors of all the files
+			errs = append(errs, gqlerror.WrapIfUnwrapped(gqlerr))
+
+			continue
 		}
 
 		mergeQueryDocument(&queryDocument, query)
 	}
+	if errs != nil {
+		return nil, newDiagnostics(errs, sourceMap(querySources))
+	}
 
 	if errs := validator.Validate(schema, &queryDocument); errs != nil {
-		return nil, xerrors.Errorf(": %w", errs)
+		return nil, newDiagnostics(errs, sourceMap(querySources))
 	}
 
 	return &queryDocument, nil
@@ -30,8 +38,12 @@ func mergeQueryDocument(q, other *ast.QueryDocument) {
 	q.Fragments = append(q.Fragments, other.Fragments...)
 }
 
+// QueryDocumentsByOperations returns a document for each operation holding the fragments it uses.
+// The error is Diagnostics holding the errors of all the operations.
 func QueryDocumentsByOperations(schema *ast.Schema, operations ast.OperationList) ([]*ast.QueryDocument, error) {
 	queryDocuments := make([]*ast.QueryDocument, 0, len(operations))
+	var errs gqlerror.List
+	sources := make(map[string]*ast.Source)
 	for _, operation := range operations {
 		fragments := fragmentsInOperationDefinition(operation)
 
@@ -41,16 +53,41 @@ func QueryDocumentsByOperations(schema *ast.Schema, operations ast.OperationList
 			Position:   nil,
 		}
 
-		if errs := validator.Validate(schema, queryDocument); errs != nil {
-			return nil, xerrors.Errorf(": %w", errs)
+		if operationErrs := validator.Validate(schema, queryDocument); operationErrs != nil {
+			errs = append(errs, operationErrs...)
+			for _, source := range documentSources(queryDocument) {
+				sources[source.Name] = source
+			}
+
+			continue
 		}
 
 		queryDocuments = append(queryDocuments, queryDocument)
 	}
+	if errs != nil {
+		return nil, newDiagnostics(errs, sources)
+	}
 
 	return queryDocuments, nil
 }
 
+// documentSources returns the query files which the operations and fragments of the document come from.
+func documentSources(queryDocument *ast.QueryDocument) []*ast.Source {
+	var sources []*ast.Source
+	for _, operation := range queryDocument.Operations {
+		if operation.Position != nil && operation.Position.Src != nil {
+			sources = append(sources, operation.Position.Src)
+		}
+	}
+	for _, fragment := range queryDocument.Fragments {
+		if fragment.Position != nil && fragment.Position.Src != nil {
+			sources = append(sources, fragment.Position.Src)
+		}
+	}
+
+	return sources
+}
+
 func fragmentsInOperationDefinition(operation *ast.OperationDefinition) ast.FragmentDefinitionList {
 	fragments := fragmentsInOperationWalker(operation.SelectionSet)
 	uniqueFragments := fragmentsUnique(fragments)
@@ -58,14 +95,16 @@ func fragmentsInOperationDefinition(operation *ast.OperationDefinition) ast.Frag
 	return uniqueFragments
 }
 
+// fragmentsUnique removes duplicated fragments keeping the order in which they are first spread,
+// so that the generated query strings are the same on every generation.
 func fragmentsUnique(fragments ast.FragmentDefinitionList) ast.FragmentDefinitionList {
-	uniqueMap := make(map[string]*ast.FragmentDefinition)
+	seen := make(map[string]bool, len(fragments))
+	uniqueFragments := make(ast.FragmentDefinitionList, 0, len(fragments))
 	for _, fragment := range fragments {
-		uniqueMap[fragment.Name] = fragment
-	}
-
-	uniqueFragments := make(ast.FragmentDefinitionList, 0, len(uniqueMap))
-	for _, fragment := range uniqueMap {
+		if seen[fragment.Name] {
+			continue
+		}
+		seen[fragment.Name] = true
 		uniqueFragments = append(uniqueFragments, fragment)
 	}
 
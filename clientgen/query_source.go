@@ -44,6 +44,29 @@ var path2regex = strings.NewReplacer(
 // LoadQuerySourceなどは、gqlgenがLoadConfigでSchemaを読み込む時の実装をコピーして一部修正している
 // **/test/*.graphqlなどに対応している
 func LoadQuerySources(queryFileNames []string) ([]*ast.Source, error) {
+	noGlobQueryFileNames, err := QueryFilenames(queryFileNames)
+	if err != nil {
+		return nil, err
+	}
+
+	querySources := make([]*ast.Source, 0, len(noGlobQueryFileNames))
+	for _, filename := range noGlobQueryFileNames {
+		filename = filepath.ToSlash(filename)
+		var err error
+		var schemaRaw []byte
+		schemaRaw, err = ioutil.ReadFile(filename)
+		if err != nil {
+			return nil, xerrors.Errorf("unable to open schema: %w", err)
+		}
+
+		querySources = append(querySources, &ast.Source{Name: filename, Input: string(schemaRaw)})
+	}
+
+	return querySources, nil
+}
+
+// QueryFilenames returns the files matching the glob patterns, which may include **.
+func QueryFilenames(queryFileNames []string) ([]string, error) {
 	var noGlobQueryFileNames config.StringList
 
 	var err error
@@ -89,18 +112,5 @@ func LoadQuerySources(queryFileNames []string) ([]*ast.Source, error) {
 		}
 	}
 
-	querySources := make([]*ast.Source, 0, len(noGlobQueryFileNames))
-	for _, filename := range noGlobQueryFileNames {
-		filename = filepath.ToSlash(filename)
-		var err error
-		var schemaRaw []byte
-		schemaRaw, err = ioutil.ReadFile(filename)
-		if err != nil {
-			return nil, xerrors.Errorf("unable to open schema: %w", err)
-		}
-
-		querySources = append(querySources, &ast.Source{Name: filename, Input: string(schemaRaw)})
-	}
-
-	return querySources, nil
+	return noGlobQueryFileNames, nil
 }
@@ -0,0 +1,62 @@
+package clientgen_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Yamashou/gqlgenc/clientgen"
+	"github.com/Yamashou/gqlgenc/internal/clientgentest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestQueryDocumentsByOperations_deterministic(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+type Query { user: User }
+type User { id: ID!, name: String, friends: [User!], posts: [Post!] }
+type Post { id: ID!, title: String, author: User }
+`})
+	sources := []*ast.Source{{Name: "query.graphql", Input: `
+query GetUser {
+  user {
+    ...UserFields
+    friends { ...FriendFields ...UserFields }
+    posts { ...PostFields }
+  }
+}
+query GetPosts { user { posts { ...PostFields } } }
+fragment UserFields on User { id name }
+fragment FriendFields on User { id }
+fragment PostFields on Post { id title author { ...AuthorFields } }
+fragment AuthorFields on User { ...UserFields }
+`}}
+
+	var first string
+	for i := 0; i < 20; i++ {
+		queryDocument, err := clientgen.ParseQueryDocuments(schema, sources)
+		if err != nil {
+			t.Fatal(err)
+		}
+		queryDocuments, err := clientgen.QueryDocumentsByOperations(schema, queryDocument.Operations)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var operations []string
+		for j, operation := range queryDocument.Operations {
+			operations = append(operations, clientgen.NewOperation(operation, queryDocuments[j], nil).Operation)
+		}
+		got := strings.Join(operations, "\n")
+
+		if i == 0 {
+			first = got
+			clientgentest.AssertGolden(t, "testdata/operations.golden", got)
+
+			continue
+		}
+		if got != first {
+			t.Fatalf("generation %d differs from the first:\n%s", i, cmp.Diff(first, got))
+		}
+	}
+}
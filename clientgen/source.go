@@ -26,21 +26,34 @@ func NewSource(schema *ast.Schema, queryDocument *ast.QueryDocument, sourceGener
 }
 
 type Fragment struct {
-	Name string
-	Type types.Type
+	Name           string
+	Type           types.Type
+	ResponseFields ResponseFieldList
 }
 
 func (s *Source) Fragments() ([]*Fragment, error) {
 	fragments := make([]*Fragment, 0, len(s.queryDocument.Fragments))
 	for _, fragment := range s.queryDocument.Fragments {
-		responseFields := s.sourceGenerator.NewResponseFields(fragment.SelectionSet)
+		responseFields, err := s.sourceGenerator.NewResponseFields(fragment.SelectionSet)
+		if err != nil {
+			return nil, xerrors.Errorf("fragment %s: %w", fragment.Name, err)
+		}
+		boundType, err := s.sourceGenerator.fragmentBinding(fragment, responseFields)
+		if err != nil {
+			return nil, xerrors.Errorf("fragment %s: %w", fragment.Name, err)
+		}
+		if boundType != nil {
+			// the fragment is decoded into the bound Go type instead of a generated one
+			continue
+		}
 		if s.sourceGenerator.cfg.Models.Exists(fragment.Name) {
 			return nil, xerrors.New(fmt.Sprintf("%s is duplicated", fragment.Name))
 		}
 
 		fragment := &Fragment{
-			Name: fragment.Name,
-			Type: responseFields.StructType(),
+			Name:           fragment.Name,
+			Type:           responseFields.StructType(),
+			ResponseFields: responseFields,
 		}
 
 		fragments = append(fragments, fragment)
@@ -57,12 +70,41 @@ func (s *Source) Fragments() ([]*Fragment, error) {
 	return fragments, nil
 }
 
+// NamedType is a type named by @goType for the selection set of a field.
+type NamedType struct {
+	Name           string
+	Type           types.Type
+	ResponseFields ResponseFieldList
+}
+
+// NamedTypes returns the types named by @goType in the fragments and the operations,
+// which are found while generating them by Fragments and OperationResponses.
+func (s *Source) NamedTypes() ([]*NamedType, error) {
+	for _, namedType := range s.sourceGenerator.namedTypes {
+		if s.sourceGenerator.cfg.Models.Exists(namedType.Name) {
+			return nil, xerrors.New(fmt.Sprintf("%s is duplicated", namedType.Name))
+		}
+	}
+
+	for _, namedType := range s.sourceGenerator.namedTypes {
+		name := namedType.Name
+		s.sourceGenerator.cfg.Models.Add(
+			name,
+			fmt.Sprintf("%s.%s", s.sourceGenerator.client.Pkg(), templates.ToGo(name)),
+		)
+	}
+
+	return s.sourceGenerator.namedTypes, nil
+}
+
 type Operation struct {
 	Name                string
 	ResponseStructName  string
 	Operation           string
 	Args                []*Argument
 	VariableDefinitions ast.VariableDefinitionList
+	// Description is the comment preceding the operation in the query file.
+	Description string
 }
 
 func NewOperation(operation *ast.OperationDefinition, queryDocument *ast.QueryDocument, args []*Argument) *Operation {
@@ -72,14 +114,18 @@ func NewOperation(operation *ast.OperationDefinition, queryDocument *ast.QueryDo
 		Operation:           queryString(queryDocument),
 		Args:                args,
 		VariableDefinitions: operation.VariableDefinitions,
+		Description:         leadingComment(operation.Position),
 	}
 }
 
-func (s *Source) Operations(queryDocuments []*ast.QueryDocument) []*Operation {
+func (s *Source) Operations(queryDocuments []*ast.QueryDocument) ([]*Operation, error) {
 	operations := make([]*Operation, 0, len(s.queryDocument.Operations))
 
 	queryDocumentsMap := queryDocumentMapByOperationName(queryDocuments)
-	operationArgsMap := s.operationArgsMapByOperationName()
+	operationArgsMap, err := s.operationArgsMapByOperationName()
+	if err != nil {
+		return nil, err
+	}
 	for _, operation := range s.queryDocument.Operations {
 		queryDocument := queryDocumentsMap[operation.Name]
 		args := operationArgsMap[operation.Name]
@@ -90,16 +136,31 @@ func (s *Source) Operations(queryDocuments []*ast.QueryDocument) []*Operation {
 		))
 	}
 
-	return operations
+	return operations, nil
 }
 
-func (s *Source) operationArgsMapByOperationName() map[string][]*Argument {
+func (s *Source) operationArgsMapByOperationName() (map[string][]*Argument, error) {
 	operationArgsMap := make(map[string][]*Argument)
 	for _, operation := range s.queryDocument.Operations {
-		operationArgsMap[operation.Name] = s.sourceGenerator.OperationArguments(operation.VariableDefinitions)
+		args, err := s.sourceGenerator.OperationArguments(operation.VariableDefinitions)
+		if err != nil {
+			return nil, xerrors.Errorf("operation %s: %w", operation.Name, err)
+		}
+		inputs := schemaInputs(operation)
+		for _, arg := range args {
+			input := inputs[arg.Variable]
+			if input == nil {
+				continue
+			}
+			if arg.DefaultValue == nil {
+				arg.DefaultValue = input.DefaultValue
+			}
+			arg.Description = input.Description
+		}
+		operationArgsMap[operation.Name] = args
 	}
 
-	return operationArgsMap
+	return operationArgsMap, nil
 }
 
 func queryDocumentMapByOperationName(queryDocuments []*ast.QueryDocument) map[string]*ast.QueryDocument {
@@ -112,30 +173,36 @@ func queryDocumentMapByOperationName(queryDocuments []*ast.QueryDocument) map[st
 	return queryDocumentMap
 }
 
+// queryString prints the document to send, without the client directives.
 func queryString(queryDocument *ast.QueryDocument) string {
 	var buf bytes.Buffer
 	astFormatter := formatter.NewFormatter(&buf)
-	astFormatter.FormatQueryDocument(queryDocument)
+	astFormatter.FormatQueryDocument(withoutClientDirectives(queryDocument))
 
 	return buf.String()
 }
 
 type OperationResponse struct {
-	Name string
-	Type types.Type
+	Name           string
+	Type           types.Type
+	ResponseFields ResponseFieldList
 }
 
 func (s *Source) OperationResponses() ([]*OperationResponse, error) {
 	operationResponse := make([]*OperationResponse, 0, len(s.queryDocument.Operations))
 	for _, operation := range s.queryDocument.Operations {
-		responseFields := s.sourceGenerator.NewResponseFields(operation.SelectionSet)
+		responseFields, err := s.sourceGenerator.NewResponseFields(operation.SelectionSet)
+		if err != nil {
+			return nil, xerrors.Errorf("operation %s: %w", operation.Name, err)
+		}
 		name := getResponseStructName(operation)
 		if s.sourceGenerator.cfg.Models.Exists(name) {
 			return nil, xerrors.New(fmt.Sprintf("%s is duplicated", name))
 		}
 		operationResponse = append(operationResponse, &OperationResponse{
-			Name: name,
-			Type: responseFields.StructType(),
+			Name:           name,
+			Type:           responseFields.StructType(),
+			ResponseFields: responseFields,
 		})
 	}
 
@@ -151,11 +218,16 @@ func (s *Source) OperationResponses() ([]*OperationResponse, error) {
 }
 
 type Query struct {
-	Name string
-	Type types.Type
+	Name           string
+	Type           types.Type
+	ResponseFields ResponseFieldList
 }
 
 func (s *Source) Query() (*Query, error) {
+	if s.schema.Query == nil {
+		return nil, nil
+	}
+
 	fields, err := s.sourceGenerator.NewResponseFieldsByDefinition(s.schema.Query)
 	if err != nil {
 		return nil, xerrors.Errorf("generate failed for query struct type : %w", err)
@@ -167,17 +239,23 @@ func (s *Source) Query() (*Query, error) {
 	)
 
 	return &Query{
-		Name: s.schema.Query.Name,
-		Type: fields.StructType(),
+		Name:           s.schema.Query.Name,
+		Type:           fields.StructType(),
+		ResponseFields: fields,
 	}, nil
 }
 
 type Mutation struct {
-	Name string
-	Type types.Type
+	Name           string
+	Type           types.Type
+	ResponseFields ResponseFieldList
 }
 
 func (s *Source) Mutation() (*Mutation, error) {
+	if s.schema.Mutation == nil {
+		return nil, nil
+	}
+
 	fields, err := s.sourceGenerator.NewResponseFieldsByDefinition(s.schema.Mutation)
 	if err != nil {
 		return nil, xerrors.Errorf("generate failed for mutation struct type : %w", err)
@@ -189,8 +267,9 @@ func (s *Source) Mutation() (*Mutation, error) {
 	)
 
 	return &Mutation{
-		Name: s.schema.Mutation.Name,
-		Type: fields.StructType(),
+		Name:           s.schema.Mutation.Name,
+		Type:           fields.StructType(),
+		ResponseFields: fields,
 	}, nil
 }
 
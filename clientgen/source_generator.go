@@ -14,37 +14,144 @@ import (
 type Argument struct {
 	Variable string
 	Type     types.Type
+	// DefaultValue is the default of the variable, or else of the argument or input field it is passed to.
+	DefaultValue *ast.Value
+	// Description is the description of the argument or input field the variable is passed to.
+	Description string
+	// omitNilDefault is whether the variable is left out of the request when it is nil and has a default value
+	omitNilDefault bool
 }
 
 type ResponseField struct {
 	Name             string
 	IsFragmentSpread bool
 	IsInlineFragment bool
-	Type             types.Type
-	Tags             []string
-	ResponseFields   ResponseFieldList
+	// IsBoundFragment is whether the fragment spread is decoded into the Go type the fragment is bound to,
+	// which is embedded into the struct of the selection set instead of having its fields flattened.
+	IsBoundFragment   bool
+	Type              types.Type
+	Tags              []string
+	ResponseFields    ResponseFieldList
+	Description       string
+	IsDeprecated      bool
+	DeprecationReason string
 }
 
 type ResponseFieldList []*ResponseField
 
 func (rs ResponseFieldList) StructType() *types.Struct {
-	vars := make([]*types.Var, 0)
-	structTags := make([]string, 0)
-	for _, filed := range rs {
+	fields := rs.structFields()
+	vars := make([]*types.Var, 0, len(fields))
+	structTags := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if field.IsBoundFragment {
+			vars = append(vars, types.NewField(0, nil, embeddedName(field.Type), field.Type, true))
+			structTags = append(structTags, "")
+
+			continue
+		}
+		vars = append(vars, types.NewVar(0, nil, templates.ToGo(field.Name), field.Type))
+		structTags = append(structTags, strings.Join(field.Tags, " "))
+	}
+
+	return types.NewStruct(vars, structTags)
+}
+
+// structFields returns the fields in the order of the fields of StructType,
+// the fields of the fragment spreads flattened and the fragments bound to Go types as a whole.
+// A field selected again under the same response key, such as a field of the selection set
+// which a fragment spread into it selects too, is a single field whose selection sets are merged.
+func (rs ResponseFieldList) structFields() ResponseFieldList {
+	fields := make(ResponseFieldList, 0, len(rs))
+	indexes := make(map[string]int)
+	add := func(field *ResponseField) {
+		name := goFieldName(field)
+		if i, ok := indexes[name]; ok {
+			fields[i] = mergeResponseFields(fields[i], field)
+
+			return
+		}
+		indexes[name] = len(fields)
+		fields = append(fields, field)
+	}
+	for _, field := range rs {
 		//  クエリーのフィールドの子階層がFragmentの場合、このフィールドにそのFragmentの型を追加する
-		if filed.IsFragmentSpread {
-			typ := filed.ResponseFields.StructType().Underlying().(*types.Struct)
-			for j := 0; j < typ.NumFields(); j++ {
-				vars = append(vars, typ.Field(j))
-				structTags = append(structTags, typ.Tag(j))
+		if field.IsFragmentSpread && !field.IsBoundFragment {
+			for _, fragmentField := range field.ResponseFields.structFields() {
+				add(fragmentField)
 			}
-		} else {
-			vars = append(vars, types.NewVar(0, nil, templates.ToGo(filed.Name), filed.Type))
-			structTags = append(structTags, strings.Join(filed.Tags, " "))
+
+			continue
 		}
+		add(field)
 	}
 
-	return types.NewStruct(vars, structTags)
+	return fields
+}
+
+// goFieldName returns the name of the Go field of the response field in the struct of its selection set.
+func goFieldName(field *ResponseField) string {
+	if field.IsBoundFragment {
+		return embeddedName(field.Type)
+	}
+
+	return templates.ToGo(field.Name)
+}
+
+// mergeResponseFields returns the field selected again as other, whose struct holds the fields of both selection sets.
+// Fields of other types than anonymous structs are the same Go type, as checkGoFieldNames checks, and are kept as is.
+func mergeResponseFields(field, other *ResponseField) *ResponseField {
+	if !mergeableTypes(field.Type, other.Type) {
+		return field
+	}
+
+	merged := *field
+	merged.ResponseFields = append(append(ResponseFieldList{}, field.ResponseFields...), other.ResponseFields...)
+	merged.Type = replaceStruct(field.Type, merged.ResponseFields.StructType())
+
+	return &merged
+}
+
+// mergeableTypes reports whether both types are anonymous structs with the same modifiers,
+// whose fields can be merged into one struct.
+func mergeableTypes(a, b types.Type) bool {
+	switch a := a.(type) {
+	case *types.Pointer:
+		b, ok := b.(*types.Pointer)
+
+		return ok && mergeableTypes(a.Elem(), b.Elem())
+	case *types.Slice:
+		b, ok := b.(*types.Slice)
+
+		return ok && mergeableTypes(a.Elem(), b.Elem())
+	case *types.Struct:
+		_, ok := b.(*types.Struct)
+
+		return ok
+	}
+
+	return false
+}
+
+// replaceStruct returns typ with the anonymous struct under its modifiers replaced with structType.
+func replaceStruct(typ types.Type, structType *types.Struct) types.Type {
+	switch typ := typ.(type) {
+	case *types.Pointer:
+		return types.NewPointer(replaceStruct(typ.Elem(), structType))
+	case *types.Slice:
+		return types.NewSlice(replaceStruct(typ.Elem(), structType))
+	}
+
+	return structType
+}
+
+// embeddedName returns the name of the field which embeds the named type or the pointer to it.
+func embeddedName(typ types.Type) string {
+	if named, ok := derefType(typ).(*types.Named); ok {
+		return named.Obj().Name()
+	}
+
+	return typ.String()
 }
 
 func (rs ResponseFieldList) IsFragment() bool {
@@ -64,26 +171,126 @@ func (rs ResponseFieldList) IsStructType() bool {
 }
 
 type SourceGenerator struct {
-	cfg    *config.Config
-	binder *config.Binder
-	client config.PackageConfig
+	cfg        *config.Config
+	binder     *config.Binder
+	client     config.PackageConfig
+	hooks      []Hook
+	structTags StructTags
+	bind       Bind
+	// omitNilDefaults leaves nil variables which have a default value out of the request
+	omitNilDefaults bool
+	// usedTypes are the types which models are generated for, if the models of the other types are left out
+	usedTypes map[string]bool
+	// boundFragments are the Go types of the bound fragments which are checked to hold the fields
+	boundFragments map[string]types.Type
+	// namedTypes are the types named by @goType in the order they are found,
+	// and namedTypeFields the types by the fields they are named on.
+	namedTypes      []*NamedType
+	namedTypeFields map[*ast.Field]*NamedType
 }
 
-func NewSourceGenerator(cfg *config.Config, client config.PackageConfig) *SourceGenerator {
+func NewSourceGenerator(cfg *config.Config, client config.PackageConfig, hooks ...Hook) *SourceGenerator {
 	return &SourceGenerator{
-		cfg:    cfg,
-		binder: cfg.NewBinder(),
-		client: client,
+		cfg:             cfg,
+		binder:          cfg.NewBinder(),
+		client:          client,
+		hooks:           hooks,
+		boundFragments:  make(map[string]types.Type),
+		namedTypeFields: make(map[*ast.Field]*NamedType),
 	}
 }
 
-func (r *SourceGenerator) NewResponseFields(selectionSet ast.SelectionSet) ResponseFieldList {
+func (r *SourceGenerator) NewResponseFields(selectionSet ast.SelectionSet) (ResponseFieldList, error) {
 	responseFields := make(ResponseFieldList, 0, len(selectionSet))
 	for _, selection := range selectionSet {
-		responseFields = append(responseFields, r.NewResponseField(selection))
+		responseField, err := r.NewResponseField(selection)
+		if err != nil {
+			return nil, err
+		}
+		responseFields = append(responseFields, responseField)
+	}
+	if err := checkGoFieldNames(selectionSet, responseFields); err != nil {
+		return nil, err
+	}
+
+	return responseFields, nil
+}
+
+// checkGoFieldNames reports the first field of the selection set whose Go field has the name of the Go field
+// of another response key, as @goField(name:) may rename a field to the name of a sibling,
+// or which is selected again under the same response key as another Go type, as @nonnull may make it.
+// The fields of fragment spreads are checked with the fields they are flattened into,
+// and the fields of selection sets which are merged with each other.
+func checkGoFieldNames(selectionSet ast.SelectionSet, responseFields ResponseFieldList) error {
+	return checkGoFields(make(map[string]*goField), selectionSet, responseFields)
+}
+
+// goField is a Go field of the struct of a selection set, with the Go fields of its own struct if it is one.
+type goField struct {
+	responseKey   string
+	responseField *ResponseField
+	fields        map[string]*goField
+}
+
+func checkGoFields(fields map[string]*goField, selectionSet ast.SelectionSet, responseFields ResponseFieldList) error {
+	for i, selection := range selectionSet {
+		responseField := responseFields[i]
+		name := goFieldName(responseField)
+		var responseKey, coordinate string
+		var position *ast.Position
+		var children ast.SelectionSet
+		switch selection := selection.(type) {
+		case *ast.Field:
+			responseKey, coordinate, position, children = selection.Alias, fieldCoordinate(selection), selection.Position, selection.SelectionSet
+		case *ast.InlineFragment:
+			responseKey, coordinate, position, children = "... on "+selection.TypeCondition, "... on "+selection.TypeCondition, selection.Position, selection.SelectionSet
+		case *ast.FragmentSpread:
+			if !responseField.IsBoundFragment {
+				if err := checkGoFields(fields, selection.Definition.SelectionSet, responseField.ResponseFields); err != nil {
+					return err
+				}
+
+				continue
+			}
+			responseKey, coordinate, position = "..."+selection.Name, "..."+selection.Name, selection.Position
+		}
+
+		field, ok := fields[name]
+		switch {
+		case !ok:
+			field = &goField{responseKey: responseKey, responseField: responseField, fields: make(map[string]*goField)}
+			fields[name] = field
+		case field.responseKey != responseKey:
+			return &SelectionError{Position: position, Coordinate: coordinate, Err: duplicatedError(name, selection, responseField, field.responseKey)}
+		case !types.Identical(field.responseField.Type, responseField.Type) && !mergeableTypes(field.responseField.Type, responseField.Type):
+			return &SelectionError{
+				Position:   position,
+				Coordinate: coordinate,
+				Err: xerrors.Errorf("%s is selected again as Go type %s instead of %s",
+					responseKey, typeString(responseField.Type), typeString(field.responseField.Type)),
+			}
+		}
+
+		if mergeableTypes(responseField.Type, responseField.Type) {
+			if err := checkGoFields(field.fields, children, responseField.ResponseFields); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// duplicatedError is the error of the selection whose Go field has the name of the Go field of responseKey.
+func duplicatedError(name string, selection ast.Selection, responseField *ResponseField, responseKey string) error {
+	switch selection.(type) {
+	case *ast.Field:
+		return xerrors.Errorf("Go field %s is duplicated with %s, rename either with @goField(name:)", name, responseKey)
+	case *ast.FragmentSpread:
+		return xerrors.Errorf("Go field %s embedding %s is duplicated with %s", name, typeString(responseField.Type), responseKey)
 	}
 
-	return responseFields
+	return xerrors.Errorf("Go field %s is duplicated with %s", name, responseKey)
 }
 
 func (r *SourceGenerator) NewResponseFieldsByDefinition(definition *ast.Definition) (ResponseFieldList, error) {
@@ -92,94 +299,241 @@ func (r *SourceGenerator) NewResponseFieldsByDefinition(definition *ast.Definiti
 		if field.Type.Name() == "__Schema" || field.Type.Name() == "__Type" {
 			continue
 		}
+		if r.isPruned(field.Type.Name()) {
+			continue
+		}
 
 		var typ types.Type
-		if field.Type.Name() == "Query" || field.Type.Name() == "Mutation" {
-			baseType, err := r.binder.FindType(r.client.Pkg().Path(), field.Type.Name())
-			if err != nil {
-				return nil, xerrors.Errorf("not found type: %w", err)
-			}
+		if r.isRootType(field.Type.Name()) {
+			// the root struct is generated in the client package, which may not exist yet
+			baseType := types.NewNamed(
+				types.NewTypeName(0, r.client.Pkg(), templates.ToGo(field.Type.Name()), nil),
+				nil,
+				nil,
+			)
 			// for recursive struct field in go
 			typ = types.NewPointer(baseType)
 		} else {
-			baseType, err := r.binder.FindTypeFromName(r.cfg.Models[field.Type.Name()].Model[0])
+			baseType, err := r.Type(field.Type.Name())
 			if err != nil {
-				return nil, xerrors.Errorf("not found type: %w", err)
+				return nil, &SelectionError{
+					Position:   field.Position,
+					Coordinate: fmt.Sprintf("%s.%s", definition.Name, field.Name),
+					Err:        err,
+				}
 			}
 			typ = r.binder.CopyModifiersFromAst(field.Type, baseType)
 		}
 
-		tags := []string{
-			fmt.Sprintf(`json:"%s"`, field.Name),
-			fmt.Sprintf(`graphql:"%s"`, field.Name),
+		tags, err := r.structTags.fieldTags(field.Name, field.Type, nil)
+		if err != nil {
+			return nil, xerrors.Errorf("%s.%s: %w", definition.Name, field.Name, err)
 		}
 
-		fields = append(fields, &ResponseField{
-			Name: field.Name,
-			Type: typ,
-			Tags: tags,
-		})
+		isDeprecated, deprecationReason := deprecation(field.Directives)
+		responseField := &ResponseField{
+			Name:              field.Name,
+			Type:              typ,
+			Tags:              tags,
+			Description:       field.Description,
+			IsDeprecated:      isDeprecated,
+			DeprecationReason: deprecationReason,
+		}
+		if err := mutateResponseField(r.hooks, responseField, field); err != nil {
+			return nil, xerrors.Errorf("%s.%s: %w", definition.Name, field.Name, err)
+		}
+		fields = append(fields, responseField)
 	}
 
 	return fields, nil
 }
 
-func (r *SourceGenerator) NewResponseField(selection ast.Selection) *ResponseField {
+// isPruned reports whether the model of the type is left out as the operations do not use the type.
+func (r *SourceGenerator) isPruned(name string) bool {
+	return r.usedTypes != nil && !r.usedTypes[name] && !r.isRootType(name) && len(r.cfg.Models[name].Model) == 0
+}
+
+// isRootType reports whether the type is the query or mutation root, whose struct is generated in the client package.
+// The subscription root is not one: the client sends no subscriptions, so no struct is generated for it,
+// and a field of the subscription type needs a Go type bound in models like any other type without a model.
+func (r *SourceGenerator) isRootType(name string) bool {
+	schema := r.cfg.Schema
+
+	return (schema.Query != nil && schema.Query.Name == name) || (schema.Mutation != nil && schema.Mutation.Name == name)
+}
+
+func (r *SourceGenerator) NewResponseField(selection ast.Selection) (*ResponseField, error) {
 	switch selection := selection.(type) {
 	case *ast.Field:
-		fieldsResponseFields := r.NewResponseFields(selection.SelectionSet)
+		if selection.Definition == nil {
+			return nil, &SelectionError{
+				Position:   selection.Position,
+				Coordinate: fieldCoordinate(selection),
+				Err:        xerrors.New("field is not defined in the schema"),
+			}
+		}
+
+		fieldsResponseFields, err := r.NewResponseFields(selection.SelectionSet)
+		if err != nil {
+			return nil, err
+		}
+
+		directives, err := newFieldDirectives(selection.Directives)
+		if err != nil {
+			return nil, &SelectionError{
+				Position:   selection.Position,
+				Coordinate: fieldCoordinate(selection),
+				Err:        err,
+			}
+		}
+		boundType := directives.Type
+		if boundType == "" {
+			boundType = r.bind[fieldCoordinate(selection)]
+		}
+		if directives.TypeName != "" && (boundType != "" || !fieldsResponseFields.IsStructType()) {
+			return nil, &SelectionError{
+				Position:   selection.Position,
+				Coordinate: fieldCoordinate(selection),
+				Err:        xerrors.Errorf("@%s names a selection set of fields generated as a struct", goTypeDirective),
+			}
+		}
 
 		var baseType types.Type
 		switch {
+		case boundType != "":
+			baseType, err = r.binder.FindTypeFromName(boundType)
+			if err != nil {
+				return nil, &SelectionError{
+					Position:   selection.Position,
+					Coordinate: fieldCoordinate(selection),
+					Err:        xerrors.Errorf("binding to %s: %w", boundType, err),
+				}
+			}
+			if len(selection.SelectionSet) > 0 {
+				if err := checkBinding(baseType, selection.Position, fieldCoordinate(selection), selection.SelectionSet, fieldsResponseFields); err != nil {
+					return nil, err
+				}
+			}
 		case fieldsResponseFields.IsBasicType():
-			baseType = r.Type(selection.Definition.Type.Name())
+			baseType, err = r.Type(selection.Definition.Type.Name())
+			if err != nil {
+				return nil, &SelectionError{
+					Position:   selection.Position,
+					Coordinate: fieldCoordinate(selection),
+					Err:        err,
+				}
+			}
 		case fieldsResponseFields.IsFragment():
 			// 子フィールドがFragmentの場合はこのFragmentがフィールドの型になる
 			// if a child field is fragment, this field type became fragment.
 			baseType = fieldsResponseFields[0].Type
 		case fieldsResponseFields.IsStructType():
 			baseType = fieldsResponseFields.StructType()
+			if directives.TypeName != "" {
+				baseType, err = r.namedType(selection, directives.TypeName, fieldsResponseFields)
+				if err != nil {
+					return nil, &SelectionError{
+						Position:   selection.Position,
+						Coordinate: fieldCoordinate(selection),
+						Err:        err,
+					}
+				}
+			}
 		default:
 			// ここにきたらバグ
 			// here is bug
-			panic("not match type")
+			return nil, &SelectionError{
+				Position:   selection.Position,
+				Coordinate: fieldCoordinate(selection),
+				Err:        xerrors.New("unexpected selection set"),
+			}
 		}
 
 		// GraphQLの定義がオプショナルのはtypeのポインタ型が返り、配列の定義場合はポインタのスライスの型になって返ってきます
 		// return pointer type then optional type or slice pointer then slice type of definition in GraphQL.
-		typ := r.binder.CopyModifiersFromAst(selection.Definition.Type, baseType)
+		schemaType := selection.Definition.Type
+		if directives.NonNull {
+			nonNull := *schemaType
+			nonNull.NonNull = true
+			schemaType = &nonNull
+		}
+		typ := r.binder.CopyModifiersFromAst(schemaType, baseType)
+
+		tags, err := r.structTags.fieldTags(selection.Alias, schemaType, selection.Directives)
+		if err != nil {
+			return nil, &SelectionError{
+				Position:   selection.Position,
+				Coordinate: fieldCoordinate(selection),
+				Err:        err,
+			}
+		}
 
-		tags := []string{
-			fmt.Sprintf(`json:"%s"`, selection.Alias),
-			fmt.Sprintf(`graphql:"%s"`, selection.Alias),
+		name := selection.Alias
+		if directives.FieldName != "" {
+			name = directives.FieldName
 		}
 
-		return &ResponseField{
-			Name:           selection.Alias,
-			Type:           typ,
-			Tags:           tags,
-			ResponseFields: fieldsResponseFields,
+		isDeprecated, deprecationReason := deprecation(selection.Definition.Directives)
+		responseField := &ResponseField{
+			Name:              name,
+			Type:              typ,
+			Tags:              tags,
+			ResponseFields:    fieldsResponseFields,
+			Description:       selection.Definition.Description,
+			IsDeprecated:      isDeprecated,
+			DeprecationReason: deprecationReason,
+		}
+		if err := mutateResponseField(r.hooks, responseField, selection.Definition); err != nil {
+			return nil, &SelectionError{
+				Position:   selection.Position,
+				Coordinate: fieldCoordinate(selection),
+				Err:        err,
+			}
 		}
 
+		return responseField, nil
+
 	case *ast.FragmentSpread:
+		if selection.Definition == nil {
+			return nil, &SelectionError{
+				Position:   selection.Position,
+				Coordinate: "..." + selection.Name,
+				Err:        xerrors.New("fragment is not defined"),
+			}
+		}
+
 		// この構造体はテンプレート側で使われることはなく、ast.FieldでFragment判定するために使用する
-		fieldsResponseFields := r.NewResponseFields(selection.Definition.SelectionSet)
-		typ := types.NewNamed(
-			types.NewTypeName(0, r.client.Pkg(), templates.ToGo(selection.Name), nil),
-			fieldsResponseFields.StructType(),
-			nil,
-		)
+		fieldsResponseFields, err := r.NewResponseFields(selection.Definition.SelectionSet)
+		if err != nil {
+			return nil, err
+		}
+		typ, err := r.fragmentBinding(selection.Definition, fieldsResponseFields)
+		if err != nil {
+			return nil, err
+		}
+		bound := typ != nil
+		if !bound {
+			typ = types.NewNamed(
+				types.NewTypeName(0, r.client.Pkg(), templates.ToGo(selection.Name), nil),
+				fieldsResponseFields.StructType(),
+				nil,
+			)
+		}
 
 		return &ResponseField{
 			Name:             selection.Name,
 			Type:             typ,
 			IsFragmentSpread: true,
+			IsBoundFragment:  bound,
 			ResponseFields:   fieldsResponseFields,
-		}
+		}, nil
 
 	case *ast.InlineFragment:
 		// InlineFragmentは子要素をそのままstructとしてもつので、ここで、構造体の型を作成します
-		fieldsResponseFields := r.NewResponseFields(selection.SelectionSet)
+		fieldsResponseFields, err := r.NewResponseFields(selection.SelectionSet)
+		if err != nil {
+			return nil, err
+		}
 
 		return &ResponseField{
 			Name:             selection.TypeCondition,
@@ -187,31 +541,86 @@ func (r *SourceGenerator) NewResponseField(selection ast.Selection) *ResponseFie
 			IsInlineFragment: true,
 			Tags:             []string{fmt.Sprintf(`graphql:"... on %s"`, selection.TypeCondition)},
 			ResponseFields:   fieldsResponseFields,
+		}, nil
+	}
+
+	return nil, xerrors.Errorf("unexpected selection type %T", selection)
+}
+
+// namedType returns the type named by @goType for the selection set of the field, generated in the client package.
+// The fields of a fragment are visited again for every spread of it, so the type is made once for each field.
+func (r *SourceGenerator) namedType(field *ast.Field, name string, responseFields ResponseFieldList) (types.Type, error) {
+	if namedType, ok := r.namedTypeFields[field]; ok {
+		return namedType.Type, nil
+	}
+	for _, namedType := range r.namedTypes {
+		if templates.ToGo(namedType.Name) == templates.ToGo(name) {
+			return nil, xerrors.Errorf("%s is duplicated", name)
 		}
 	}
 
-	panic("unexpected selection type")
+	namedType := &NamedType{
+		Name: name,
+		Type: types.NewNamed(
+			types.NewTypeName(0, r.client.Pkg(), templates.ToGo(name), nil),
+			responseFields.StructType(),
+			nil,
+		),
+		ResponseFields: responseFields,
+	}
+	r.namedTypes = append(r.namedTypes, namedType)
+	r.namedTypeFields[field] = namedType
+
+	return namedType.Type, nil
 }
 
-func (r *SourceGenerator) OperationArguments(variableDefinitions ast.VariableDefinitionList) []*Argument {
+func (r *SourceGenerator) OperationArguments(variableDefinitions ast.VariableDefinitionList) ([]*Argument, error) {
 	argumentTypes := make([]*Argument, 0, len(variableDefinitions))
 	for _, v := range variableDefinitions {
+		typ, err := r.Type(v.Type.Name())
+		if err != nil {
+			return nil, &SelectionError{
+				Position:   v.Position,
+				Coordinate: "$" + v.Variable,
+				Err:        err,
+			}
+		}
 		argumentTypes = append(argumentTypes, &Argument{
-			Variable: v.Variable,
-			Type:     r.binder.CopyModifiersFromAst(v.Type, r.Type(v.Type.Name())),
+			Variable:       v.Variable,
+			Type:           r.binder.CopyModifiersFromAst(v.Type, typ),
+			DefaultValue:   v.DefaultValue,
+			omitNilDefault: r.omitNilDefaults,
 		})
 	}
 
-	return argumentTypes
+	return argumentTypes, nil
 }
 
 // Typeの引数に渡すtypeNameは解析した結果からselectionなどから求めた型の名前を渡さなければいけない
-func (r *SourceGenerator) Type(typeName string) types.Type {
-	goType, err := r.binder.FindTypeFromName(r.cfg.Models[typeName].Model[0])
+func (r *SourceGenerator) Type(typeName string) (types.Type, error) {
+	model := r.cfg.Models[typeName]
+	if len(model.Model) == 0 {
+		return nil, &MissingModelError{TypeName: typeName}
+	}
+
+	goType, err := r.binder.FindTypeFromName(model.Model[0])
 	if err != nil {
-		// 実装として正しいtypeNameを渡していれば必ず見つかるはずなのでpanic
-		panic(fmt.Sprintf("%+v", err))
+		return nil, xerrors.Errorf("%s: %w", typeName, err)
+	}
+	// variables are marshaled as values in a map, so a type which marshals JSON only through a pointer,
+	// such as big.Int, is used as a pointer
+	if marshalsByPointer(goType) {
+		return types.NewPointer(goType), nil
+	}
+
+	return goType, nil
+}
+
+func marshalsByPointer(t types.Type) bool {
+	if _, ok := t.(*types.Named); !ok {
+		return false
 	}
 
-	return goType
+	return types.NewMethodSet(t).Lookup(nil, "MarshalJSON") == nil &&
+		types.NewMethodSet(types.NewPointer(t)).Lookup(nil, "MarshalJSON") != nil
 }
@@ -0,0 +1,182 @@
+package clientgen_test
+
+import (
+	"errors"
+	"go/types"
+	"testing"
+
+	"github.com/99designs/gqlgen/codegen/config"
+	"github.com/Yamashou/gqlgenc/clientgen"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestSource_Mutation_readOnly(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: `type Query { name: String }`})
+	cfg := &config.Config{Schema: schema}
+	source := clientgen.NewSource(schema, &ast.QueryDocument{}, clientgen.NewSourceGenerator(cfg, config.PackageConfig{}))
+
+	mutation, err := source.Mutation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mutation != nil {
+		t.Errorf("want no mutation, got %+v", mutation)
+	}
+}
+
+func TestSource_Operations_missingModel(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+scalar DateTime
+type Query { now(after: DateTime): DateTime }
+`})
+	query := gqlparser.MustLoadQuery(schema, `query Now($after: DateTime) { now(after: $after) }`)
+	query.Operations[0].VariableDefinitions[0].Position.Src = &ast.Source{Name: "query.graphql"}
+	cfg := &config.Config{Schema: schema, Models: config.TypeMap{}}
+	source := clientgen.NewSource(schema, query, clientgen.NewSourceGenerator(cfg, config.PackageConfig{}))
+
+	_, err := source.Operations(nil)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	var selectionErr *clientgen.SelectionError
+	if !errors.As(err, &selectionErr) {
+		t.Fatalf("want SelectionError, got %v", err)
+	}
+	if want := "query.graphql:1:11: $after: no Go type is bound to DateTime, add it to models"; selectionErr.Error() != want {
+		t.Errorf("want %q, got %q", want, selectionErr.Error())
+	}
+	var missingModelErr *clientgen.MissingModelError
+	if !errors.As(err, &missingModelErr) || missingModelErr.TypeName != "DateTime" {
+		t.Errorf("want MissingModelError for DateTime, got %v", err)
+	}
+}
+
+func TestSource_OperationResponses_goTypeOnLeaf(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+directive @goType(name: String!) on FIELD
+type Query { name: String }
+`})
+	query := gqlparser.MustLoadQuery(schema, `query Name { name @goType(name: "Name") }`)
+	cfg := &config.Config{Schema: schema, Models: config.TypeMap{"String": {Model: config.StringList{"github.com/99designs/gqlgen/graphql.String"}}}}
+	source := clientgen.NewSource(schema, query, clientgen.NewSourceGenerator(cfg, config.PackageConfig{}))
+
+	_, err := source.OperationResponses()
+	var selectionErr *clientgen.SelectionError
+	if !errors.As(err, &selectionErr) {
+		t.Fatalf("want SelectionError, got %v", err)
+	}
+	if want := "@goType names a selection set of fields generated as a struct"; selectionErr.Err.Error() != want {
+		t.Errorf("want %q, got %q", want, selectionErr.Err.Error())
+	}
+}
+
+func TestSource_OperationResponses_duplicatedGoField(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+directive @goField(name: String, type: String) on FIELD
+type Query { name: String }
+`})
+	cfg := config.DefaultConfig()
+	cfg.Schema = schema
+	if err := cfg.Init(); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			name:  "renamed to the name of a sibling",
+			query: `query Name { first: name @goField(name: "Second") second: name }`,
+			want:  "Query.name: Go field Second is duplicated with first, rename either with @goField(name:)",
+		},
+		{
+			name:  "renamed to the same name",
+			query: `query Name { first: name @goField(name: "Name") second: name @goField(name: "Name") }`,
+			want:  "Query.name: Go field Name is duplicated with first, rename either with @goField(name:)",
+		},
+		{
+			name:  "renamed to the name of a field of a fragment",
+			query: `query Name { first: name @goField(name: "Second") ...Names } fragment Names on Query { second: name }`,
+			want:  "Query.name: Go field Second is duplicated with first, rename either with @goField(name:)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := gqlparser.MustLoadQuery(schema, tt.query)
+			source := clientgen.NewSource(schema, query, clientgen.NewSourceGenerator(cfg, config.PackageConfig{}))
+
+			_, err := source.OperationResponses()
+			var selectionErr *clientgen.SelectionError
+			if !errors.As(err, &selectionErr) {
+				t.Fatalf("want SelectionError, got %v", err)
+			}
+			if got := selectionErr.Coordinate + ": " + selectionErr.Err.Error(); got != tt.want {
+				t.Errorf("want %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSource_OperationResponses_sameResponseKey(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+directive @nonnull on FIELD
+type Query { user(id: ID!): User }
+type User { id: ID! name: String posts: [Post!]! }
+type Post { id: ID! title: String }
+`})
+	cfg := config.DefaultConfig()
+	cfg.Schema = schema
+	if err := cfg.Init(); err != nil {
+		t.Fatal(err)
+	}
+	responses := func(query string) ([]*clientgen.OperationResponse, error) {
+		source := clientgen.NewSource(schema, gqlparser.MustLoadQuery(schema, query), clientgen.NewSourceGenerator(cfg, config.PackageConfig{}))
+
+		return source.OperationResponses()
+	}
+
+	got, err := responses(`
+query GetUser($id: ID!) { user(id: $id) { id posts { id } ...UserFields } }
+fragment UserFields on User { id name posts { title } }
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "struct{User *struct{ID string; Posts []*struct{ID string; Title *string}; Name *string}}"
+	if got := types.TypeString(withoutTags(got[0].Type), nil); got != want {
+		t.Errorf("want %s, got %s", want, got)
+	}
+
+	_, err = responses(`
+query GetUser($id: ID!) { user(id: $id) { posts { title @nonnull } ...UserFields } }
+fragment UserFields on User { posts { title } }
+`)
+	var selectionErr *clientgen.SelectionError
+	if !errors.As(err, &selectionErr) {
+		t.Fatalf("want SelectionError, got %v", err)
+	}
+	if want := "Post.title: title is selected again as Go type *string instead of string"; selectionErr.Coordinate+": "+selectionErr.Err.Error() != want {
+		t.Errorf("want %q, got %q", want, selectionErr.Coordinate+": "+selectionErr.Err.Error())
+	}
+}
+
+// withoutTags returns the type with the tags of its structs removed, for comparing it as a string.
+func withoutTags(typ types.Type) types.Type {
+	switch typ := typ.(type) {
+	case *types.Pointer:
+		return types.NewPointer(withoutTags(typ.Elem()))
+	case *types.Slice:
+		return types.NewSlice(withoutTags(typ.Elem()))
+	case *types.Struct:
+		fields := make([]*types.Var, typ.NumFields())
+		for i := range fields {
+			fields[i] = types.NewField(0, nil, typ.Field(i).Name(), withoutTags(typ.Field(i).Type()), typ.Field(i).Embedded())
+		}
+
+		return types.NewStruct(fields, nil)
+	}
+
+	return typ
+}
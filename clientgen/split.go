@@ -0,0 +1,180 @@
+package clientgen
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/99designs/gqlgen/codegen/config"
+	"github.com/vektah/gqlparser/v2/ast"
+	"golang.org/x/xerrors"
+)
+
+// The ways to split the generated client into files.
+const (
+	// SplitByFile generates a file for the operations of each query file.
+	SplitByFile = "file"
+	// SplitByOperation generates a file for each operation.
+	SplitByOperation = "operation"
+)
+
+// splitFile is a file of the split client, holding some of the fragments or operations.
+type splitFile struct {
+	Filename           string
+	Fragments          []*Fragment
+	NamedTypes         []*NamedType
+	Operations         []*Operation
+	OperationResponses []*OperationResponse
+}
+
+// splitFiles returns the files the fragments and operations are split into, next to the client file.
+// Operations and operationResponses are in the order of the operations of queryDocument.
+func splitFiles(split string, client config.PackageConfig, queryDocument *ast.QueryDocument, fragments []*Fragment, namedTypes []*NamedType, operations []*Operation, operationResponses []*OperationResponse) ([]*splitFile, error) {
+	var files []*splitFile
+	byFilename := make(map[string]*splitFile)
+	owners := make(map[string]string)
+	add := func(name, owner string) (*splitFile, error) {
+		filename := splitFilename(client.Filename, snakeCase(name))
+		if file, ok := byFilename[filename]; ok {
+			if owners[filename] != owner {
+				return nil, xerrors.Errorf("%s and %s are both generated into %s", owners[filename], owner, filename)
+			}
+
+			return file, nil
+		}
+
+		file := &splitFile{Filename: filename}
+		files = append(files, file)
+		byFilename[filename] = file
+		owners[filename] = owner
+
+		return file, nil
+	}
+
+	if len(fragments) > 0 || len(namedTypes) > 0 {
+		file, err := add("fragments", "the fragments")
+		if err != nil {
+			return nil, err
+		}
+		file.Fragments = fragments
+		file.NamedTypes = namedTypes
+	}
+
+	for i, operation := range queryDocument.Operations {
+		var name, owner string
+		switch split {
+		case SplitByFile:
+			if operation.Position == nil || operation.Position.Src == nil {
+				return nil, xerrors.Errorf("the query file of operation %s is unknown", operation.Name)
+			}
+			owner = operation.Position.Src.Name
+			name = strings.TrimSuffix(filepath.Base(owner), filepath.Ext(owner))
+		case SplitByOperation:
+			owner = "operation " + operation.Name
+			name = operation.Name
+		default:
+			return nil, xerrors.Errorf("unknown split %q", split)
+		}
+
+		file, err := add(name, owner)
+		if err != nil {
+			return nil, err
+		}
+		file.Operations = append(file.Operations, operations[i])
+		file.OperationResponses = append(file.OperationResponses, operationResponses[i])
+	}
+
+	return files, nil
+}
+
+// splitFilename returns the name of the file for the snake case name next to the client file.
+// It ends with _gen.go so that it never looks like a test or a file for some GOOS or GOARCH.
+func splitFilename(clientFilename, name string) string {
+	stem := strings.TrimSuffix(clientFilename, ".go")
+
+	return stem + "_" + name + "_gen.go"
+}
+
+func snakeCase(s string) string {
+	var b strings.Builder
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case unicode.IsUpper(r):
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsUpper(runes[i-1]) && unicode.IsLower(runes[i+1]))) {
+				b.WriteRune('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+
+	return b.String()
+}
+
+// SplitFiles returns the files of the split client next to the client file which gqlgenc has generated,
+// telling them from other files by the header of generated code.
+func SplitFiles(clientFilename string) ([]string, error) {
+	filenames, err := filepath.Glob(splitFilename(clientFilename, "*"))
+	if err != nil {
+		return nil, xerrors.Errorf("invalid client filename %s: %w", clientFilename, err)
+	}
+
+	var generated []string
+	for _, filename := range filenames {
+		ok, err := hasGeneratedHeader(filename)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			generated = append(generated, filename)
+		}
+	}
+
+	return generated, nil
+}
+
+func hasGeneratedHeader(filename string) (bool, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return false, xerrors.Errorf("failed to read %s: %w", filename, err)
+	}
+	defer f.Close()
+
+	line, err := bufio.NewReader(f).ReadString('\n')
+	if err != nil && line == "" {
+		return false, nil
+	}
+
+	return strings.TrimSpace(line) == generatedHeader, nil
+}
+
+// removeStaleFiles removes the files of the split client which were generated before but not this time,
+// so that switching the split or removing operations leaves nothing behind.
+func removeStaleFiles(clientFilename string, files []*splitFile) error {
+	written := make(map[string]bool, len(files))
+	for _, file := range files {
+		written[file.Filename] = true
+	}
+
+	filenames, err := SplitFiles(clientFilename)
+	if err != nil {
+		return err
+	}
+	for _, filename := range filenames {
+		if written[filename] {
+			continue
+		}
+		if err := os.Remove(filename); err != nil {
+			return xerrors.Errorf("failed to remove stale %s: %w", filename, err)
+		}
+	}
+
+	return nil
+}
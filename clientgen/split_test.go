@@ -0,0 +1,49 @@
+package clientgen_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Yamashou/gqlgenc/clientgen"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSplitFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "split")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"client.go":              "// Code generated by github.com/Yamashou/gqlgenc, DO NOT EDIT.\n\npackage gen\n",
+		"client_user_gen.go":     "// Code generated by github.com/Yamashou/gqlgenc, DO NOT EDIT.\n\npackage gen\n",
+		"client_helpers_gen.go":  "package gen\n",
+		"client_other.go":        "// Code generated by github.com/Yamashou/gqlgenc, DO NOT EDIT.\n\npackage gen\n",
+		"client_empty_gen.go":    "",
+		"models_user_gen.go":     "// Code generated by github.com/Yamashou/gqlgenc, DO NOT EDIT.\n\npackage gen\n",
+		"client_search_gen.go":   "// Code generated by github.com/Yamashou/gqlgenc, DO NOT EDIT.\n\npackage gen\n",
+		"client_gqlgen_gen.go":   "// Code generated by github.com/99designs/gqlgen, DO NOT EDIT.\n\npackage gen\n",
+		"client_fragment_gen.go": "// Code generated by github.com/Yamashou/gqlgenc, DO NOT EDIT.",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := clientgen.SplitFiles(filepath.Join(dir, "client.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		filepath.Join(dir, "client_fragment_gen.go"),
+		filepath.Join(dir, "client_search_gen.go"),
+		filepath.Join(dir, "client_user_gen.go"),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected files (-want +got):\n%s", diff)
+	}
+}
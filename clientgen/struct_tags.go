@@ -0,0 +1,100 @@
+package clientgen
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"golang.org/x/xerrors"
+)
+
+// goTagDirective is the client directive overriding the struct tags of a field,
+// with an argument for each tag of StructTags and omitempty, as in @goTag(db: "user_name", omitempty: false).
+// A tag set to "-" is written without omitempty.
+const goTagDirective = "goTag"
+
+// StructTags are the tags added to the fields of the response structs besides json and graphql.
+type StructTags struct {
+	// Tags are the keys of the tags holding the name of the field in the response as json does, such as yaml or db.
+	Tags []string `yaml:"tags,omitempty"`
+	// Omitempty adds omitempty to the json tag and the tags of the fields which are nullable in the schema.
+	Omitempty bool `yaml:"omitempty,omitempty"`
+}
+
+var tagKey = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+// Validate reports whether the keys of the tags can be written into struct tags and used as arguments of @goTag.
+func (t StructTags) Validate() error {
+	seen := map[string]bool{"json": true, "graphql": true, "omitempty": true}
+	for _, key := range t.Tags {
+		if !tagKey.MatchString(key) {
+			return xerrors.Errorf("invalid tag %q", key)
+		}
+		if seen[key] {
+			return xerrors.Errorf("tag %q can not be added", key)
+		}
+		seen[key] = true
+	}
+
+	return nil
+}
+
+// goTagDefinition defines @goTag with the arguments for the tags.
+func (t StructTags) goTagDefinition() *ast.DirectiveDefinition {
+	arguments := ast.ArgumentDefinitionList{
+		{Name: "omitempty", Type: ast.NamedType("Boolean", nil)},
+	}
+	for _, key := range t.Tags {
+		arguments = append(arguments, &ast.ArgumentDefinition{Name: key, Type: ast.NamedType("String", nil)})
+	}
+
+	return &ast.DirectiveDefinition{
+		Description: "Overrides the struct tags of the field in the generated code.",
+		Name:        goTagDirective,
+		Arguments:   arguments,
+		Locations:   []ast.DirectiveLocation{ast.LocationField},
+	}
+}
+
+// fieldTags returns the struct tags of the field named name in the response, of the type in the schema,
+// overridden by @goTag among the directives.
+func (t StructTags) fieldTags(name string, typ *ast.Type, directives ast.DirectiveList) ([]string, error) {
+	omitempty := t.Omitempty && !typ.NonNull
+	values := make(map[string]string, len(t.Tags))
+	if directive := directives.ForName(goTagDirective); directive != nil {
+		for _, argument := range directive.Arguments {
+			value, err := constantValue(directive, argument)
+			if err != nil {
+				return nil, err
+			}
+			switch value := value.(type) {
+			case bool:
+				omitempty = value
+			case string:
+				values[argument.Name] = value
+			}
+		}
+	}
+
+	tag := func(key, value string) string {
+		if omitempty && value != "-" {
+			value += ",omitempty"
+		}
+
+		return fmt.Sprintf(`%s:"%s"`, key, value)
+	}
+
+	tags := []string{
+		tag("json", name),
+		fmt.Sprintf(`graphql:"%s"`, name),
+	}
+	for _, key := range t.Tags {
+		value, ok := values[key]
+		if !ok {
+			value = name
+		}
+		tags = append(tags, tag(key, value))
+	}
+
+	return tags, nil
+}
@@ -0,0 +1,28 @@
+package clientgen_test
+
+import (
+	"testing"
+
+	"github.com/Yamashou/gqlgenc/clientgen"
+)
+
+func TestStructTags_Validate(t *testing.T) {
+	tests := []struct {
+		tags    []string
+		wantErr bool
+	}{
+		{tags: []string{"yaml", "mapstructure", "db"}},
+		{tags: []string{"json"}, wantErr: true},
+		{tags: []string{"graphql"}, wantErr: true},
+		{tags: []string{"omitempty"}, wantErr: true},
+		{tags: []string{"db", "db"}, wantErr: true},
+		{tags: []string{"my-tag"}, wantErr: true},
+		{tags: []string{""}, wantErr: true},
+	}
+	for _, tt := range tests {
+		err := clientgen.StructTags{Tags: tt.tags}.Validate()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%v: want error %v, got %v", tt.tags, tt.wantErr, err)
+		}
+	}
+}
@@ -1,27 +1,156 @@
 package clientgen
 
 import (
+	"fmt"
+	"go/types"
+	"strconv"
+	"strings"
+	"text/template"
+
 	"github.com/99designs/gqlgen/codegen/config"
 	"github.com/99designs/gqlgen/codegen/templates"
+	"github.com/vektah/gqlparser/v2/ast"
 	"golang.org/x/xerrors"
 )
 
+// generatedHeader is the first line of every file gqlgenc generates.
+const generatedHeader = "// Code generated by github.com/Yamashou/gqlgenc, DO NOT EDIT."
+
+// TemplateData is the data the client template is executed with.
+// Besides the functions of gqlgen templates, such as go, ref and reserveImport,
+// the template can call structType to print the struct type of ResponseFields and comment to print a // comment.
+type TemplateData struct {
+	// Client is whether the file holds the Client type and NewClient.
+	// Only the client file does when the client is split into several files.
+	Client bool
+	// Query and Mutation are the structs of the query and mutation types holding every root field,
+	// nil when the schema has no such type or the root structs are skipped.
+	Query    *Query
+	Mutation *Mutation
+	// Fragment, Operation and OperationResponse are the fragments, the operations and the responses
+	// of the operations in the file.
+	Fragment          []*Fragment
+	Operation         []*Operation
+	OperationResponse []*OperationResponse
+	// NamedType are the types named by @goType, which are in the file of the fragments when the client is split.
+	NamedType []*NamedType
+	// Schema is the schema the operations are sent to.
+	Schema *ast.Schema
+}
+
+// RenderTemplate renders the client into a single file with the default template.
 func RenderTemplate(cfg *config.Config, query *Query, mutation *Mutation, fragments []*Fragment, operations []*Operation, operationResponses []*OperationResponse, client config.PackageConfig) error {
+	return render(cfg, client, "", client.Filename, &TemplateData{
+		Client:            true,
+		Query:             query,
+		Mutation:          mutation,
+		Fragment:          fragments,
+		Operation:         operations,
+		OperationResponse: operationResponses,
+		Schema:            cfg.Schema,
+	})
+}
+
+// renderSplit renders the client, the root structs and the fragments and operations of each file into separate files.
+func renderSplit(cfg *config.Config, query *Query, mutation *Mutation, files []*splitFile, client config.PackageConfig, tmpl string) error {
+	if err := render(cfg, client, tmpl, client.Filename, &TemplateData{
+		Client:   true,
+		Query:    query,
+		Mutation: mutation,
+		Schema:   cfg.Schema,
+	}); err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if err := render(cfg, client, tmpl, file.Filename, &TemplateData{
+			Fragment:          file.Fragments,
+			NamedType:         file.NamedTypes,
+			Operation:         file.Operations,
+			OperationResponse: file.OperationResponses,
+			Schema:            cfg.Schema,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// render renders the data into filename with tmpl, or with the default template if tmpl is empty.
+func render(cfg *config.Config, client config.PackageConfig, tmpl, filename string, data *TemplateData) error {
 	if err := templates.Render(templates.Options{
 		PackageName: client.Package,
-		Filename:    client.Filename,
-		Data: map[string]interface{}{
-			"Query":             query,
-			"Mutation":          mutation,
-			"Fragment":          fragments,
-			"Operation":         operations,
-			"OperationResponse": operationResponses,
+		Template:    tmpl,
+		Filename:    filename,
+		Data:        data,
+		Funcs: template.FuncMap{
+			"structType": structType,
+			"comment":    comment,
 		},
 		Packages:   cfg.Packages,
-		PackageDoc: "// Code generated by github.com/Yamashou/gqlgenc, DO NOT EDIT.\n",
+		PackageDoc: generatedHeader + "\n",
 	}); err != nil {
-		return xerrors.Errorf("%s generating failed: %w", client.Filename, err)
+		return xerrors.Errorf("%s generating failed: %w", filename, err)
 	}
 
 	return nil
 }
+
+// structType prints the struct which ResponseFieldList.StructType returns like ref does,
+// with comments on the fields which types.Struct can't hold.
+func structType(fields ResponseFieldList) string {
+	structType := fields.StructType()
+	responseFields := fields.structFields()
+
+	var buf strings.Builder
+	buf.WriteString("struct {\n")
+	for i := 0; i < structType.NumFields(); i++ {
+		writeField(&buf, structType.Field(i), structType.Tag(i), responseFields[i])
+	}
+	buf.WriteString("}")
+
+	return buf.String()
+}
+
+// writeField prints the field of the struct, documented by the response field it is made of.
+func writeField(buf *strings.Builder, field *types.Var, tag string, responseField *ResponseField) {
+	if responseField.Description != "" {
+		fmt.Fprintf(buf, "%s\n", comment(responseField.Description))
+		if responseField.IsDeprecated {
+			buf.WriteString("//\n")
+		}
+	}
+	if responseField.IsDeprecated {
+		fmt.Fprintf(buf, "// Deprecated: %s\n", strings.ReplaceAll(responseField.DeprecationReason, "\n", "\n// "))
+	}
+
+	if field.Embedded() {
+		buf.WriteString(templates.CurrentImports.LookupType(field.Type()))
+	} else {
+		fields := responseField.ResponseFields
+		// a field whose only child is an inline fragment has the type of the fragment's fields
+		if !responseField.IsInlineFragment && fields.IsFragment() && fields[0].IsInlineFragment {
+			fields = fields[0].ResponseFields
+		}
+		fmt.Fprintf(buf, "%s %s", field.Name(), fieldType(field.Type(), fields))
+	}
+	if tag != "" {
+		fmt.Fprintf(buf, " %s", strconv.Quote(tag))
+	}
+	buf.WriteString("\n")
+}
+
+// fieldType prints typ, replacing the struct built from fields with structType.
+func fieldType(typ types.Type, fields ResponseFieldList) string {
+	switch typ := typ.(type) {
+	case *types.Pointer:
+		return "*" + fieldType(typ.Elem(), fields)
+	case *types.Slice:
+		return "[]" + fieldType(typ.Elem(), fields)
+	case *types.Struct:
+		return structType(fields)
+	}
+
+	return templates.CurrentImports.LookupType(typ)
+}
@@ -0,0 +1,18 @@
+package domain
+
+type Role string
+
+type User struct {
+	ID    string
+	Name  string `graphql:"name"`
+	Role  Role
+	Posts []*Post
+}
+
+type Post struct {
+	Title int
+}
+
+type Friend struct {
+	Name string
+}
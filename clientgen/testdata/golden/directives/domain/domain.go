@@ -0,0 +1,5 @@
+package domain
+
+type Post struct {
+	Title string `json:"title"`
+}
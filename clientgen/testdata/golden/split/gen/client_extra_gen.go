@@ -0,0 +1,4 @@
+package gen
+
+// Extra is written by hand next to the generated files, which must be kept.
+const Extra = 1
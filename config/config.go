@@ -2,6 +2,8 @@ package config
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
@@ -10,7 +12,9 @@ import (
 
 	"github.com/99designs/gqlgen/codegen/config"
 	"github.com/Yamashou/gqlgenc/client"
+	"github.com/Yamashou/gqlgenc/clientgen"
 	"github.com/Yamashou/gqlgenc/introspection"
+	"github.com/vektah/gqlparser/v2"
 	"github.com/vektah/gqlparser/v2/ast"
 	"github.com/vektah/gqlparser/v2/validator"
 	"golang.org/x/xerrors"
@@ -18,14 +22,19 @@ import (
 )
 
 type Config struct {
-	Model    config.PackageConfig `yaml:"model,omitempty"`
-	Client   config.PackageConfig `yaml:"client,omitempty"`
-	Models   config.TypeMap       `yaml:"models,omitempty"`
-	Endpoint EndPointConfig       `yaml:"endpoint"`
-	Query    []string             `yaml:"query"`
+	SchemaFilename config.StringList    `yaml:"schema,omitempty"`
+	Model          config.PackageConfig `yaml:"model,omitempty"`
+	Client         ClientConfig         `yaml:"client,omitempty"`
+	Models         config.TypeMap       `yaml:"models,omitempty"`
+	Endpoint       *EndPointConfig      `yaml:"endpoint,omitempty"`
+	Query          []string             `yaml:"query"`
+	SchemaLock     string               `yaml:"schema_lock,omitempty"`
+	Generate       GenerateConfig       `yaml:"generate,omitempty"`
 
 	// gqlgen config struct
 	GQLConfig *config.Config `yaml:"-"`
+	// Warn receives the warnings of the generation, which are dropped when nil.
+	Warn func(warning string) `yaml:"-"`
 }
 
 type EndPointConfig struct {
@@ -33,6 +42,30 @@ type EndPointConfig struct {
 	Headers map[string]string `yaml:"headers,omitempty"`
 }
 
+// ClientConfig is where the client is generated, and optionally the template it is generated with.
+type ClientConfig struct {
+	config.PackageConfig `yaml:",inline"`
+	// Template is the file of a template replacing the default one, executed with clientgen.TemplateData.
+	Template string `yaml:"template,omitempty"`
+}
+
+// GenerateConfig changes what is generated.
+type GenerateConfig struct {
+	// SkipRootStructs skips the structs of the query and mutation types holding the root fields of the schema.
+	SkipRootStructs bool `yaml:"skip_root_structs,omitempty"`
+	// Split splits the client into a file for the operations of each query file with "file",
+	// or for each operation with "operation".
+	Split string `yaml:"split,omitempty"`
+	// StructTags are the tags added to the fields of the responses besides json and graphql.
+	StructTags clientgen.StructTags `yaml:"struct_tags,omitempty"`
+	// Bind binds fragments by name and fields by schema coordinate such as User.posts to existing Go types
+	// instead of generating types for their selection sets.
+	Bind clientgen.Bind `yaml:"bind,omitempty"`
+	// OmitNilDefaults leaves nil variables and nil fields of input objects which have a default value
+	// out of the request, so that the default applies instead of null. They can no longer send an explicit null.
+	OmitNilDefaults bool `yaml:"omit_nil_defaults,omitempty"`
+}
+
 func findCfg(fileName string) (string, error) {
 	dir, err := os.Getwd()
 	if err != nil {
@@ -54,6 +87,9 @@ func findCfgInDir(dir, fileName string) string {
 	return path
 }
 
+// enableModelJSONOmitemptyTag is false so that the models keep sending the null of nullable fields.
+var enableModelJSONOmitemptyTag = false
+
 func LoadConfig(filename string) (*Config, error) {
 	var cfg Config
 	file, err := findCfg(filename)
@@ -70,23 +106,95 @@ func LoadConfig(filename string) (*Config, error) {
 		return nil, xerrors.Errorf("unable to parse config: %w", err)
 	}
 
+	// gqlgen adds the models of the built-in scalars to the map
+	if cfg.Models == nil {
+		cfg.Models = config.TypeMap{}
+	}
 	cfg.GQLConfig = &config.Config{
 		Model:  cfg.Model,
 		Models: cfg.Models,
 		// TODO: gqlgen must be set exec but client not used
-		Exec:                     config.PackageConfig{Filename: "generated.go"},
+		Exec:                     config.ExecConfig{Filename: "generated.go"},
 		Directives:               map[string]config.DirectiveConfig{},
 		OmitSliceElementPointers: true,
+		// the client package has its own root structs, and the models are plain data as they were before gqlgen had these options
+		OmitRootModels:              true,
+		OmitGetters:                 true,
+		StructFieldsAlwaysPointers:  true,
+		EnableModelJsonOmitemptyTag: &enableModelJSONOmitemptyTag,
 	}
 
 	if err := cfg.Client.Check(); err != nil {
 		return nil, xerrors.Errorf("config.exec: %w", err)
 	}
 
+	switch cfg.Generate.Split {
+	case "", clientgen.SplitByFile, clientgen.SplitByOperation:
+	default:
+		return nil, xerrors.Errorf("generate.split: unknown split %q, must be %s or %s", cfg.Generate.Split, clientgen.SplitByFile, clientgen.SplitByOperation)
+	}
+
+	if err := cfg.Generate.StructTags.Validate(); err != nil {
+		return nil, xerrors.Errorf("generate.struct_tags: %w", err)
+	}
+	if err := cfg.Generate.Bind.Validate(); err != nil {
+		return nil, xerrors.Errorf("generate.bind: %w", err)
+	}
+
+	if len(cfg.SchemaFilename) == 0 && cfg.Endpoint == nil {
+		return nil, xerrors.New("neither schema nor endpoint is specified")
+	}
+
 	return &cfg, nil
 }
 
+// LoadSchema loads the schema from the local files given by schema if any,
+// otherwise by introspecting the endpoint.
 func (c *Config) LoadSchema(ctx context.Context) error {
+	var schema *ast.Schema
+	var err error
+	if len(c.SchemaFilename) > 0 {
+		schema, err = LoadLocalSchema(c.SchemaFilename)
+	} else {
+		schema, err = c.loadRemoteSchema(ctx)
+	}
+	if err != nil {
+		return err
+	}
+
+	if schema.Query == nil {
+		schema.Query = &ast.Definition{
+			Kind: ast.Object,
+			Name: "Query",
+		}
+		schema.Types["Query"] = schema.Query
+	}
+
+	c.GQLConfig.Schema = schema
+
+	return nil
+}
+
+func (c *Config) loadRemoteSchema(ctx context.Context) (*ast.Schema, error) {
+	res, err := c.IntrospectEndpoint(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("load remote schema failed: %w", err)
+	}
+
+	schema, err := validateIntrospection(*res)
+	if err != nil {
+		return nil, xerrors.Errorf("load remote schema failed: %w", err)
+	}
+
+	return schema, nil
+}
+
+// IntrospectEndpoint sends the introspection query to the endpoint and returns the raw result.
+func (c *Config) IntrospectEndpoint(ctx context.Context) (*introspection.Query, error) {
+	if c.Endpoint == nil {
+		return nil, xerrors.New("endpoint is not specified")
+	}
+
 	addHeader := func(_ context.Context, req *http.Request) {
 		for key, value := range c.Endpoint.Headers {
 			req.Header.Set(key, value)
@@ -95,43 +203,136 @@ func (c *Config) LoadSchema(ctx context.Context) error {
 
 	endpoint, err := url.Parse(c.Endpoint.URL)
 	if err != nil {
-		return xerrors.Errorf("load remote schema failed: %w", err)
+		return nil, xerrors.Errorf("invalid endpoint: %w", err)
 	}
 	httpCl, err := client.NewDefaultClientPool(endpoint)
 	if err != nil {
-		return xerrors.Errorf("load remote schema failed: %w", err)
+		return nil, xerrors.Errorf("invalid endpoint: %w", err)
 	}
 	gqlclient := client.NewClient(
 		httpCl,
 		[]client.HTTPRequestOption{addHeader},
 		nil,
 	)
-	schema, err := LoadRemoteSchema(ctx, gqlclient)
+
+	return Introspect(ctx, gqlclient, c.Warn)
+}
+
+// LoadLocalSchema loads the schema from SDL files, or from a single file
+// holding the JSON result of an introspection query.
+func LoadLocalSchema(filenames []string) (*ast.Schema, error) {
+	sources, err := clientgen.LoadQuerySources(filenames)
 	if err != nil {
-		return xerrors.Errorf("load remote schema failed: %w", err)
+		return nil, xerrors.Errorf("load local schema failed: %w", err)
 	}
-	if schema.Query == nil {
-		schema.Query = &ast.Definition{
-			Kind: ast.Object,
-			Name: "Query",
+	if len(sources) == 0 {
+		return nil, xerrors.Errorf("load local schema failed: no file matches %v", filenames)
+	}
+
+	for _, source := range sources {
+		if filepath.Ext(source.Name) != ".json" {
+			continue
 		}
-		schema.Types["Query"] = schema.Query
+		if len(sources) != 1 {
+			return nil, xerrors.Errorf("load local schema failed: %s can not be combined with other schema files", source.Name)
+		}
+
+		schema, err := loadIntrospectionSchema(source)
+		if err != nil {
+			return nil, xerrors.Errorf("load local schema failed: %w", err)
+		}
+
+		return schema, nil
 	}
 
-	c.GQLConfig.Schema = schema
+	schema, gqlerr := gqlparser.LoadSchema(sources...)
+	if gqlerr != nil {
+		return nil, xerrors.Errorf("load local schema failed: %w", gqlerr)
+	}
 
-	return nil
+	return schema, nil
+}
+
+func loadIntrospectionSchema(source *ast.Source) (*ast.Schema, error) {
+	// accept both the whole response {"data":{"__schema":...}} and the bare {"__schema":...}
+	var response struct {
+		Data *introspection.Query `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(source.Input), &response); err != nil {
+		return nil, xerrors.Errorf("%s: %w", source.Name, err)
+	}
+
+	res := response.Data
+	if res == nil {
+		res = &introspection.Query{}
+		if err := json.Unmarshal([]byte(source.Input), res); err != nil {
+			return nil, xerrors.Errorf("%s: %w", source.Name, err)
+		}
+	}
+	if res.Schema.Types == nil {
+		return nil, xerrors.Errorf("%s: __schema is not found", source.Name)
+	}
+
+	schema, err := validateIntrospection(*res)
+	if err != nil {
+		return nil, xerrors.Errorf("%s: %w", source.Name, err)
+	}
+
+	return schema, nil
 }
 
 func LoadRemoteSchema(ctx context.Context, gqlclient *client.Client) (*ast.Schema, error) {
-	var res introspection.Query
-	if err := gqlclient.Post(ctx, &res, introspection.Introspection, nil, nil, nil); err != nil {
-		return nil, xerrors.Errorf("introspection query failed: %w", err)
+	res, err := Introspect(ctx, gqlclient, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return validateIntrospection(*res)
+}
+
+// maxTypeRefDepth bounds the depth of types Introspect asks for, since servers may limit the depth of queries.
+const maxTypeRefDepth = 64
+
+// Introspect asks the server which introspection features it supports,
+// then sends the introspection query which requests all of them.
+// Warn receives why the server did not answer which features it supports, and may be nil.
+// A type nested deeper than maxTypeRefDepth is an error wrapping introspection.ErrTypeRefTruncated.
+func Introspect(ctx context.Context, gqlclient *client.Client, warn func(warning string)) (*introspection.Query, error) {
+	var capabilities introspection.CapabilitiesQueryResult
+	if err := gqlclient.Post(ctx, &capabilities, introspection.CapabilitiesQuery, nil, nil, nil); err != nil {
+		// servers which disallow the probe still answer the introspection query every server supports
+		if warn != nil {
+			warn(fmt.Sprintf("introspecting without optional features, since the server did not answer which it supports: %v", err))
+		}
+		capabilities = introspection.CapabilitiesQueryResult{}
 	}
 
-	schema, err := validator.ValidateSchemaDocument(introspection.ParseIntrospectionQuery(res))
+	// ask for deeper types until no type is truncated
+	for depth := introspection.DefaultTypeRefDepth; ; depth *= 2 {
+		var res introspection.Query
+		query := introspection.BuildQuery(capabilities.Capabilities(), depth)
+		if err := gqlclient.Post(ctx, &res, query, nil, nil, nil); err != nil {
+			return nil, xerrors.Errorf("introspection query failed: %w", err)
+		}
+
+		if !res.TypeRefTruncated() {
+			return &res, nil
+		}
+		if depth >= maxTypeRefDepth {
+			return nil, xerrors.Errorf("introspection query asked for types %d deep: %w", depth, introspection.ErrTypeRefTruncated)
+		}
+	}
+}
+
+func validateIntrospection(res introspection.Query) (*ast.Schema, error) {
+	doc, err := introspection.ParseIntrospectionQuery(res)
 	if err != nil {
-		return nil, xerrors.Errorf("validation error: %w", err)
+		return nil, xerrors.Errorf("invalid introspection result: %w", err)
+	}
+
+	schema, gqlerr := validator.ValidateSchemaDocument(doc)
+	if gqlerr != nil {
+		return nil, xerrors.Errorf("validation error: %w", gqlerr)
 	}
 
 	return schema, nil
@@ -0,0 +1,175 @@
+package config_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	gqlgenconfig "github.com/99designs/gqlgen/codegen/config"
+	"github.com/Yamashou/gqlgenc/client"
+	"github.com/Yamashou/gqlgenc/config"
+	"github.com/Yamashou/gqlgenc/introspection"
+	"golang.org/x/xerrors"
+)
+
+func TestConfig_LoadSchema_local(t *testing.T) {
+	cfg := &config.Config{
+		SchemaFilename: gqlgenconfig.StringList{"testdata/*.graphql"},
+		GQLConfig:      &gqlgenconfig.Config{},
+	}
+	if err := cfg.LoadSchema(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	schema := cfg.GQLConfig.Schema
+	if schema.Query == nil || schema.Query.Name != "Query" {
+		t.Fatalf("unexpected query type: %+v", schema.Query)
+	}
+	if schema.Types["User"] == nil {
+		t.Error("User type is not loaded")
+	}
+}
+
+func TestConfig_LoadSchema_noMatch(t *testing.T) {
+	cfg := &config.Config{
+		SchemaFilename: gqlgenconfig.StringList{"testdata/*.notfound"},
+		GQLConfig:      &gqlgenconfig.Config{},
+	}
+	if err := cfg.LoadSchema(context.Background()); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestLoadLocalSchema_introspection(t *testing.T) {
+	schema, err := config.LoadLocalSchema([]string{"testdata/introspection.json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if schema.Query == nil || schema.Query.Name != "Query" {
+		t.Fatalf("unexpected query type: %+v", schema.Query)
+	}
+	if schema.Mutation == nil || schema.Mutation.Name != "Mutation" {
+		t.Errorf("unexpected mutation type: %+v", schema.Mutation)
+	}
+	if user := schema.Types["User"]; user == nil || user.Fields.ForName("name") == nil {
+		t.Errorf("User type is not loaded: %+v", user)
+	}
+}
+
+func TestIntrospect_capabilities(t *testing.T) {
+	introspectionResult, err := ioutil.ReadFile("testdata/introspection.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name           string
+		probe          string
+		wantInQuery    string
+		wantNotInQuery string
+		wantWarning    bool
+	}{
+		{
+			name:        "supported",
+			probe:       `{"data":{"type":{"fields":[{"name":"specifiedByURL"}]}}}`,
+			wantInQuery: "specifiedByURL: specifiedByURL",
+		},
+		{
+			name:           "probe rejected",
+			wantNotInQuery: "specifiedByURL",
+			wantWarning:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var introspectionQuery string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var req client.Request
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Fatal(err)
+				}
+				if req.Query == introspection.CapabilitiesQuery {
+					if tt.probe == "" {
+						w.WriteHeader(http.StatusBadRequest)
+
+						return
+					}
+					_, _ = w.Write([]byte(tt.probe))
+
+					return
+				}
+				introspectionQuery = req.Query
+				_, _ = w.Write(introspectionResult)
+			}))
+			defer server.Close()
+
+			var warnings []string
+			cfg := &config.Config{
+				Endpoint: &config.EndPointConfig{URL: server.URL},
+				Warn:     func(warning string) { warnings = append(warnings, warning) },
+			}
+			res, err := cfg.IntrospectEndpoint(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := len(warnings) > 0; got != tt.wantWarning {
+				t.Errorf("got warnings %q, want warning %v", warnings, tt.wantWarning)
+			}
+			if res.Schema.QueryType.Name == nil || *res.Schema.QueryType.Name != "Query" {
+				t.Errorf("unexpected query type: %+v", res.Schema.QueryType)
+			}
+			if tt.wantInQuery != "" && !strings.Contains(introspectionQuery, tt.wantInQuery) {
+				t.Errorf("query does not contain %q:\n%s", tt.wantInQuery, introspectionQuery)
+			}
+			if tt.wantNotInQuery != "" && strings.Contains(introspectionQuery, tt.wantNotInQuery) {
+				t.Errorf("query contains %q:\n%s", tt.wantNotInQuery, introspectionQuery)
+			}
+		})
+	}
+}
+
+func TestIntrospect_truncated(t *testing.T) {
+	var result struct {
+		Data introspection.Query `json:"data"`
+	}
+	introspectionResult, err := ioutil.ReadFile("testdata/introspection.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(introspectionResult, &result); err != nil {
+		t.Fatal(err)
+	}
+	// a list whose element type is never answered, however deep the query asks
+	result.Data.Schema.Types[0].Fields[0].Type = introspection.TypeRef{Kind: introspection.TypeKindList}
+
+	var queries int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req client.Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		if req.Query == introspection.CapabilitiesQuery {
+			_, _ = w.Write([]byte(`{"data":{}}`))
+
+			return
+		}
+		queries++
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			t.Fatal(err)
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{Endpoint: &config.EndPointConfig{URL: server.URL}}
+	if _, err := cfg.IntrospectEndpoint(context.Background()); !xerrors.Is(err, introspection.ErrTypeRefTruncated) {
+		t.Errorf("got error %v, want %v", err, introspection.ErrTypeRefTruncated)
+	}
+	if queries == 0 || queries > 8 {
+		t.Errorf("sent %d introspection queries, want the depth bounded", queries)
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/Yamashou/gqlgenc/clientgen"
+	"golang.org/x/xerrors"
+)
+
+// printGenerateError prints the error in the format. As text, the diagnostics of the query files are printed
+// if the error holds them, or else the error. As JSON, every error located in the query or schema files
+// is printed as a diagnostic.
+func printGenerateError(err error, format string) {
+	if format == "json" {
+		diagnostics, ok := clientgen.ErrorDiagnostics(err)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "%+v", err.Error())
+
+			return
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(diagnostics); err != nil {
+			fmt.Fprintf(os.Stderr, "%+v", err.Error())
+		}
+
+		return
+	}
+
+	var diagnostics clientgen.Diagnostics
+	if !xerrors.As(err, &diagnostics) {
+		fmt.Fprintf(os.Stderr, "%+v", err.Error())
+
+		return
+	}
+	fmt.Fprintln(os.Stderr, diagnostics.Error())
+}
+
+// printWarning prints a warning of the generation.
+func printWarning(warning string) {
+	fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+}
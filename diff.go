@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	gqlgenconfig "github.com/99designs/gqlgen/codegen/config"
+	"github.com/Yamashou/gqlgenc/config"
+	"github.com/Yamashou/gqlgenc/generator"
+	"github.com/Yamashou/gqlgenc/schemadiff"
+	"github.com/vektah/gqlparser/v2/ast"
+	"golang.org/x/xerrors"
+)
+
+const diffUsage = `usage: gqlgenc diff <old schema> <new schema>
+
+A schema is an endpoint URL, a JSON file of an introspection result or comma separated SDL files.
+If .gqlgenc.yml exists, the operations in its query files affected by each breaking change are listed.
+Exits with 1 if there are breaking changes.
+`
+
+func runDiff(ctx context.Context, args []string) int {
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	flags.Usage = func() { fmt.Fprint(os.Stderr, diffUsage) }
+	_ = flags.Parse(args)
+	if flags.NArg() != 2 {
+		flags.Usage()
+
+		return 2
+	}
+
+	cfg, err := config.LoadConfig(configFilename)
+	if err != nil && !xerrors.Is(err, os.ErrNotExist) {
+		fmt.Fprintf(os.Stderr, "%+v", err.Error())
+
+		return 2
+	}
+
+	oldSchema, err := loadSchema(ctx, cfg, flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%+v", err.Error())
+
+		return 4
+	}
+	newSchema, err := loadSchema(ctx, cfg, flags.Arg(1))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%+v", err.Error())
+
+		return 4
+	}
+
+	var operations ast.OperationList
+	if cfg != nil {
+		queryDocument, err := generator.NewClientPlugin(cfg).QueryDocument(oldSchema)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "operations are invalid against the old schema: %+v", err.Error())
+
+			return 4
+		}
+		operations = queryDocument.Operations
+	}
+
+	usages := make([]schemadiff.Usage, 0, len(operations))
+	for _, operation := range operations {
+		usages = append(usages, schemadiff.NewUsage(oldSchema, operation))
+	}
+
+	breaking := false
+	for _, change := range schemadiff.Compare(oldSchema, newSchema) {
+		fmt.Printf("%-9s %s\n", change.Criticality, change.Message)
+		if change.Criticality != schemadiff.Breaking {
+			continue
+		}
+		breaking = true
+
+		for i, operation := range operations {
+			if usages[i].Affects(change) {
+				fmt.Printf("          affects %s (%s:%d)\n", operation.Name, operation.Position.Src.Name, operation.Position.Line)
+			}
+		}
+	}
+
+	if breaking {
+		return 1
+	}
+
+	return 0
+}
+
+func loadSchema(ctx context.Context, cfg *config.Config, location string) (*ast.Schema, error) {
+	c := &config.Config{GQLConfig: &gqlgenconfig.Config{}, Warn: printWarning}
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		c.Endpoint = &config.EndPointConfig{URL: location}
+		if cfg != nil && cfg.Endpoint != nil {
+			c.Endpoint.Headers = cfg.Endpoint.Headers
+		}
+	} else {
+		c.SchemaFilename = strings.Split(location, ",")
+	}
+
+	if err := c.LoadSchema(ctx); err != nil {
+		return nil, xerrors.Errorf("%s: %w", location, err)
+	}
+
+	return c.GQLConfig.Schema, nil
+}
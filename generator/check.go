@@ -0,0 +1,216 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/99designs/gqlgen/api"
+	"github.com/Yamashou/gqlgenc/clientgen"
+	"github.com/Yamashou/gqlgenc/config"
+	"github.com/Yamashou/gqlgenc/internal/unified"
+	"github.com/Yamashou/gqlgenc/sdl"
+	"golang.org/x/xerrors"
+)
+
+// CheckSchemaLock returns the unified diff of the SDL from the schema lock to the schema, empty if the schema
+// has not changed, loading the schema unless it already is. A missing lock is reported as the diff creating it,
+// and a lock edited by hand is an error.
+func CheckSchemaLock(ctx context.Context, cfg *config.Config) (string, error) {
+	if cfg.SchemaLock == "" {
+		return "", nil
+	}
+
+	if cfg.GQLConfig.Schema == nil {
+		if err := cfg.LoadSchema(ctx); err != nil {
+			return "", xerrors.Errorf("failed to load schema: %w\n", err)
+		}
+	}
+	schemaLock := sdl.NewLock(cfg.GQLConfig.Schema)
+	name := displayName(cfg.SchemaLock)
+
+	if _, err := os.Stat(cfg.SchemaLock); os.IsNotExist(err) {
+		return unified.Diff(name, name, "", schemaLock.String()), nil
+	}
+	lock, err := sdl.ReadLock(cfg.SchemaLock)
+	if err != nil {
+		return "", xerrors.Errorf("failed to read schema lock: %w\n", err)
+	}
+	if diff := lock.Diff(schemaLock); diff != "" {
+		return fmt.Sprintf("the schema differs from %s:\n%s", name, diff), nil
+	}
+
+	return "", nil
+}
+
+// Check generates the code as Generate does, and returns the unified diff from the generated files on disk
+// to the files generation writes, empty if they are up to date. The schema lock is compared before generating,
+// so that a change of the schema is reported along with the error of a generation it breaks.
+//
+// gqlgen renders the models to their file and loads the packages it binds to from disk,
+// so the files are generated in place, and the files on disk are restored once compared.
+func Check(ctx context.Context, cfg *config.Config, option ...api.Option) (diff string, err error) {
+	lockDiff, err := CheckSchemaLock(ctx, cfg)
+	if err != nil {
+		return "", err
+	}
+
+	filenames, err := outputFilenames(cfg)
+	if err != nil {
+		return lockDiff, err
+	}
+	saved, err := saveFiles(filenames)
+	if err != nil {
+		return lockDiff, err
+	}
+	defer func() {
+		if restoreErr := saved.restore(); restoreErr != nil && err == nil {
+			err = restoreErr
+		}
+	}()
+
+	if err := Generate(ctx, cfg, option...); err != nil {
+		return lockDiff, err
+	}
+
+	// the files of the split client may be added or removed
+	generated, err := clientgen.SplitFiles(cfg.Client.Filename)
+	if err != nil {
+		return lockDiff, err
+	}
+	for _, filename := range generated {
+		if filename, err = filepath.Abs(filename); err != nil {
+			return lockDiff, xerrors.Errorf("invalid filename %s: %w", filename, err)
+		}
+		if _, ok := saved[filename]; !ok {
+			filenames = append(filenames, filename)
+			saved[filename] = savedFile{}
+		}
+	}
+	sort.Strings(filenames)
+
+	// the schema lock is compared as SDL rather than as a file
+	var lockFilename string
+	if cfg.SchemaLock != "" {
+		if lockFilename, err = filepath.Abs(cfg.SchemaLock); err != nil {
+			return lockDiff, xerrors.Errorf("invalid filename %s: %w", cfg.SchemaLock, err)
+		}
+	}
+
+	diffs := []string{lockDiff}
+	for _, filename := range filenames {
+		if filename == lockFilename {
+			continue
+		}
+		content, err := readFileIfExists(filename)
+		if err != nil {
+			return lockDiff, err
+		}
+
+		name := displayName(filename)
+		diffs = append(diffs, unified.Diff(name, name, saved[filename].content, content))
+	}
+
+	return strings.Join(diffs, ""), nil
+}
+
+// outputFilenames returns the files generation writes, including the files of the split client on disk.
+func outputFilenames(cfg *config.Config) ([]string, error) {
+	filenames := []string{cfg.Client.Filename}
+	if cfg.Model.IsDefined() {
+		filenames = append(filenames, cfg.Model.Filename)
+	}
+	if cfg.SchemaLock != "" {
+		filenames = append(filenames, cfg.SchemaLock)
+	}
+	splitFilenames, err := clientgen.SplitFiles(cfg.Client.Filename)
+	if err != nil {
+		return nil, err
+	}
+	filenames = append(filenames, splitFilenames...)
+
+	for i, filename := range filenames {
+		abs, err := filepath.Abs(filename)
+		if err != nil {
+			return nil, xerrors.Errorf("invalid filename %s: %w", filename, err)
+		}
+		filenames[i] = abs
+	}
+
+	return filenames, nil
+}
+
+// savedFile is the content of a file before generation.
+type savedFile struct {
+	content string
+	exists  bool
+}
+
+// savedFiles are the files generation writes, by absolute path.
+type savedFiles map[string]savedFile
+
+func saveFiles(filenames []string) (savedFiles, error) {
+	saved := make(savedFiles, len(filenames))
+	for _, filename := range filenames {
+		content, err := ioutil.ReadFile(filename)
+		if os.IsNotExist(err) {
+			saved[filename] = savedFile{}
+
+			continue
+		}
+		if err != nil {
+			return nil, xerrors.Errorf("failed to read %s: %w", filename, err)
+		}
+		saved[filename] = savedFile{content: string(content), exists: true}
+	}
+
+	return saved, nil
+}
+
+// restore writes the files back as they were, removing those which did not exist.
+func (s savedFiles) restore() error {
+	for filename, file := range s {
+		if !file.exists {
+			if err := os.Remove(filename); err != nil && !os.IsNotExist(err) {
+				return xerrors.Errorf("failed to restore %s: %w", filename, err)
+			}
+
+			continue
+		}
+		if err := ioutil.WriteFile(filename, []byte(file.content), 0o644); err != nil {
+			return xerrors.Errorf("failed to restore %s: %w", filename, err)
+		}
+	}
+
+	return nil
+}
+
+func readFileIfExists(filename string) (string, error) {
+	content, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", xerrors.Errorf("failed to read %s: %w", filename, err)
+	}
+
+	return string(content), nil
+}
+
+// displayName returns the slash separated path of the file relative to the working directory if it is under it.
+func displayName(filename string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return filepath.ToSlash(filename)
+	}
+	rel, err := filepath.Rel(wd, filename)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(filename)
+	}
+
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,53 @@
+package generator_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gqlgenconfig "github.com/99designs/gqlgen/codegen/config"
+	"github.com/Yamashou/gqlgenc/config"
+	"github.com/Yamashou/gqlgenc/generator"
+	"github.com/Yamashou/gqlgenc/sdl"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestCheckSchemaLock(t *testing.T) {
+	lockFilename := filepath.Join(t.TempDir(), "schema.lock")
+	newConfig := func(input string) *config.Config {
+		return &config.Config{
+			SchemaLock: lockFilename,
+			GQLConfig:  &gqlgenconfig.Config{Schema: gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: input})},
+		}
+	}
+	oldSchema := `type Query { a: String }`
+
+	diff, err := generator.CheckSchemaLock(context.Background(), newConfig(oldSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(diff, "+type Query") {
+		t.Errorf("missing lock: got diff %q, want the lock created", diff)
+	}
+
+	if err := sdl.NewLock(newConfig(oldSchema).GQLConfig.Schema).Write(lockFilename); err != nil {
+		t.Fatal(err)
+	}
+	diff, err = generator.CheckSchemaLock(context.Background(), newConfig(oldSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != "" {
+		t.Errorf("unchanged schema: got diff %q, want none", diff)
+	}
+
+	diff, err = generator.CheckSchemaLock(context.Background(), newConfig(`type Query { a: String b: Int }`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(diff, "+\tb: Int") {
+		t.Errorf("changed schema: got diff %q, want the added field", diff)
+	}
+}
@@ -0,0 +1,60 @@
+package generator
+
+import (
+	"fmt"
+	"go/types"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/99designs/gqlgen/plugin/modelgen"
+	"github.com/Yamashou/gqlgenc/config"
+)
+
+// mutateInputDefaults documents the default values of input fields on the generated models,
+// and with OmitNilDefaults, leaves out nil fields which have a default value so that the default applies instead of null.
+func mutateInputDefaults(cfg *config.Config) modelgen.BuildMutateHook {
+	return func(b *modelgen.ModelBuild) *modelgen.ModelBuild {
+		for _, model := range b.Models {
+			def := cfg.GQLConfig.Schema.Types[model.Name]
+			if def == nil {
+				continue
+			}
+			for _, field := range model.Fields {
+				name := strings.Split(reflect.StructTag(field.Tag).Get("json"), ",")[0]
+				fieldDef := def.Fields.ForName(name)
+				if fieldDef == nil || fieldDef.DefaultValue == nil {
+					continue
+				}
+
+				defaults := fmt.Sprintf("Defaults to %s.", fieldDef.DefaultValue.String())
+				if field.Description == "" {
+					field.Description = defaults
+				} else {
+					field.Description += "\n" + defaults
+				}
+
+				if _, ok := field.Type.(*types.Pointer); ok && cfg.Generate.OmitNilDefaults {
+					field.Tag = withOmitempty(field.Tag)
+				}
+			}
+		}
+
+		return b
+	}
+}
+
+// withOmitempty adds omitempty to the json key of the struct tag, keeping the other keys.
+func withOmitempty(tag string) string {
+	value, ok := reflect.StructTag(tag).Lookup("json")
+	if !ok {
+		return tag
+	}
+	for _, option := range strings.Split(value, ",")[1:] {
+		if option == "omitempty" {
+			return tag
+		}
+	}
+
+	return strings.Replace(tag, "json:"+strconv.Quote(value), "json:"+strconv.Quote(value+",omitempty"), 1)
+}
@@ -0,0 +1,81 @@
+package generator_test
+
+import (
+	"go/types"
+	"testing"
+
+	gqlgenconfig "github.com/99designs/gqlgen/codegen/config"
+	"github.com/99designs/gqlgen/plugin/modelgen"
+	"github.com/Yamashou/gqlgenc/config"
+	"github.com/Yamashou/gqlgenc/generator"
+	"github.com/google/go-cmp/cmp"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestMutateInputDefaults(t *testing.T) {
+	schema, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+type Query { users(filter: UserFilter): [String!]! }
+
+input UserFilter {
+  "Matches the role"
+  role: String = "USER"
+  limit: Int! = 10
+  name: String
+}
+`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	type field struct{ Description, Tag string }
+	tests := []struct {
+		name            string
+		omitNilDefaults bool
+		want            []field
+	}{
+		{
+			name: "documented",
+			want: []field{
+				{"Matches the role\nDefaults to \"USER\".", `json:"role" xml:"role"`},
+				{"Defaults to 10.", `json:"limit"`},
+				{"", `json:"name"`},
+			},
+		},
+		{
+			name:            "omit nil defaults",
+			omitNilDefaults: true,
+			want: []field{
+				{"Matches the role\nDefaults to \"USER\".", `json:"role,omitempty" xml:"role"`},
+				{"Defaults to 10.", `json:"limit"`},
+				{"", `json:"name"`},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				GQLConfig: &gqlgenconfig.Config{Schema: schema},
+				Generate:  config.GenerateConfig{OmitNilDefaults: tt.omitNilDefaults},
+			}
+			str := types.Typ[types.String]
+			model := &modelgen.Object{
+				Name: "UserFilter",
+				Fields: []*modelgen.Field{
+					{Name: "role", Description: "Matches the role", Type: types.NewPointer(str), Tag: `json:"role" xml:"role"`},
+					{Name: "limit", Type: types.Typ[types.Int], Tag: `json:"limit"`},
+					{Name: "name", Type: types.NewPointer(str), Tag: `json:"name"`},
+				},
+			}
+
+			generator.MutateInputDefaults(cfg)(&modelgen.ModelBuild{Models: []*modelgen.Object{model}})
+
+			got := make([]field, 0, len(model.Fields))
+			for _, f := range model.Fields {
+				got = append(got, field{f.Description, f.Tag})
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("(-want +got):\n%s", diff)
+			}
+		})
+	}
+}
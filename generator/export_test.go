@@ -0,0 +1,11 @@
+package generator
+
+var (
+	BindScalars         = bindScalars
+	ScalarModels        = scalarModels
+	UsedTypes           = usedTypes
+	PruneModels         = pruneModels
+	MutateInputDefaults = mutateInputDefaults
+)
+
+const FallbackScalarModel = fallbackScalarModel
@@ -6,27 +6,61 @@ import (
 	"github.com/99designs/gqlgen/api"
 	"github.com/99designs/gqlgen/plugin"
 	"github.com/99designs/gqlgen/plugin/modelgen"
+	"github.com/Yamashou/gqlgenc/clientgen"
 	"github.com/Yamashou/gqlgenc/config"
+	"github.com/Yamashou/gqlgenc/sdl"
+	"github.com/vektah/gqlparser/v2/ast"
 	"golang.org/x/xerrors"
 )
 
 func Generate(ctx context.Context, cfg *config.Config, option ...api.Option) error {
 	var plugins []plugin.Plugin
+	var modelPlugin *modelgen.Plugin
 	if cfg.Model.IsDefined() {
-		plugins = append(plugins, modelgen.New())
+		// the hook needs the schema, which is not loaded yet
+		modelPlugin = &modelgen.Plugin{}
+		plugins = append(plugins, modelPlugin)
 	}
 	for _, o := range option {
 		o(cfg.GQLConfig, &plugins)
 	}
 
-	if err := cfg.LoadSchema(ctx); err != nil {
-		return xerrors.Errorf("failed to load schema: %w\n", err)
+	// the schema may already be loaded, for example by watch mode regenerating for changed queries
+	if cfg.GQLConfig.Schema == nil {
+		if err := cfg.LoadSchema(ctx); err != nil {
+			return xerrors.Errorf("failed to load schema: %w\n", err)
+		}
 	}
 
 	if err := cfg.GQLConfig.Init(); err != nil {
 		return xerrors.Errorf("generating core failed: %w\n", err)
 	}
 
+	clientPlugin := findClientPlugin(plugins)
+	queryDocument, err := parseQueryDocument(cfg, clientPlugin)
+	if err != nil {
+		return xerrors.Errorf("failed to load queries: %w\n", err)
+	}
+	if clientPlugin != nil {
+		// the client is generated for the document parsed here, rather than parsing the query files again
+		clientPlugin.Document = queryDocument
+	}
+	used := usedTypes(cfg.GQLConfig.Schema, queryDocument)
+	warnings := bindScalars(cfg.GQLConfig, used)
+	if cfg.Warn != nil {
+		for _, warning := range warnings {
+			cfg.Warn(warning)
+		}
+	}
+
+	if modelPlugin != nil {
+		modelPlugin.MutateHook = modelMutateHook(cfg, used)
+		// the root structs refer only to the models which are generated
+		if clientPlugin != nil {
+			clientPlugin.UsedTypes = used
+		}
+	}
+
 	for _, p := range plugins {
 		if mut, ok := p.(plugin.ConfigMutator); ok {
 			err := mut.MutateConfig(cfg.GQLConfig)
@@ -36,5 +70,57 @@ func Generate(ctx context.Context, cfg *config.Config, option ...api.Option) err
 		}
 	}
 
+	if cfg.SchemaLock != "" {
+		if err := sdl.NewLock(cfg.GQLConfig.Schema).Write(cfg.SchemaLock); err != nil {
+			return xerrors.Errorf("failed to write schema lock: %w\n", err)
+		}
+	}
+
+	return nil
+}
+
+// NewClientPlugin returns the plugin generating the client as cfg configures it, extended with the hooks.
+func NewClientPlugin(cfg *config.Config, hooks ...clientgen.Hook) *clientgen.Plugin {
+	clientPlugin := clientgen.New(cfg.Query, cfg.Client.PackageConfig)
+	clientPlugin.SkipRootStructs = cfg.Generate.SkipRootStructs
+	clientPlugin.Split = cfg.Generate.Split
+	clientPlugin.Template = cfg.Client.Template
+	clientPlugin.StructTags = cfg.Generate.StructTags
+	clientPlugin.Bind = cfg.Generate.Bind
+	clientPlugin.OmitNilDefaults = cfg.Generate.OmitNilDefaults
+	clientPlugin.Hooks = hooks
+	clientPlugin.Warn = cfg.Warn
+
+	return clientPlugin
+}
+
+// modelMutateHook returns the hook which generates models only for the types the operations use,
+// and documents the default values of input fields.
+func modelMutateHook(cfg *config.Config, used map[string]bool) modelgen.BuildMutateHook {
+	prune := pruneModels(cfg, used)
+	mutateDefaults := mutateInputDefaults(cfg)
+
+	return func(b *modelgen.ModelBuild) *modelgen.ModelBuild {
+		return mutateDefaults(prune(b))
+	}
+}
+
+// findClientPlugin returns the plugin generating the client, or nil if there is none.
+func findClientPlugin(plugins []plugin.Plugin) *clientgen.Plugin {
+	for _, p := range plugins {
+		if clientPlugin, ok := p.(*clientgen.Plugin); ok {
+			return clientPlugin
+		}
+	}
+
 	return nil
 }
+
+// parseQueryDocument parses the operations the client is generated for, as changed by the hooks of the client plugin if any.
+func parseQueryDocument(cfg *config.Config, clientPlugin *clientgen.Plugin) (*ast.QueryDocument, error) {
+	if clientPlugin == nil {
+		clientPlugin = NewClientPlugin(cfg)
+	}
+
+	return clientPlugin.QueryDocument(cfg.GQLConfig.Schema)
+}
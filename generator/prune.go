@@ -0,0 +1,108 @@
+package generator
+
+import (
+	"github.com/99designs/gqlgen/plugin/modelgen"
+	"github.com/Yamashou/gqlgenc/config"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// usedTypes returns the names of the types the operations refer to:
+// the types of the variables of the operations with the input objects they hold,
+// and the enums and scalars the operations select.
+// Selected objects, interfaces and unions need no models since the responses are anonymous structs.
+func usedTypes(schema *ast.Schema, queryDocument *ast.QueryDocument) map[string]bool {
+	used := map[string]bool{}
+	// addType adds the type and the types of its fields, which its model refers to
+	var addType func(name string)
+	addType = func(name string) {
+		if used[name] {
+			return
+		}
+		used[name] = true
+		if def := schema.Types[name]; def != nil {
+			for _, field := range def.Fields {
+				addType(field.Type.Name())
+			}
+		}
+	}
+
+	var walk func(selectionSet ast.SelectionSet)
+	walk = func(selectionSet ast.SelectionSet) {
+		for _, selection := range selectionSet {
+			switch selection := selection.(type) {
+			case *ast.Field:
+				if selection.Definition != nil {
+					if def := schema.Types[selection.Definition.Type.Name()]; def != nil && (def.Kind == ast.Enum || def.Kind == ast.Scalar) {
+						addType(def.Name)
+					}
+				}
+				walk(selection.SelectionSet)
+			case *ast.InlineFragment:
+				walk(selection.SelectionSet)
+			}
+		}
+	}
+
+	for _, operation := range queryDocument.Operations {
+		for _, variable := range operation.VariableDefinitions {
+			addType(variable.Type.Name())
+		}
+		walk(operation.SelectionSet)
+	}
+	// fragment spreads are not followed, since every fragment is walked here
+	for _, fragment := range queryDocument.Fragments {
+		walk(fragment.SelectionSet)
+	}
+
+	return used
+}
+
+// pruneModels leaves out the models of the types which are not used,
+// and unbinds them so that nothing refers to the Go types which are no longer generated,
+// such as the root structs which leave out the root fields of these types.
+func pruneModels(cfg *config.Config, used map[string]bool) modelgen.BuildMutateHook {
+	return func(b *modelgen.ModelBuild) *modelgen.ModelBuild {
+		keep := func(name string) bool {
+			if used[name] {
+				return true
+			}
+			delete(cfg.GQLConfig.Models, name)
+
+			return false
+		}
+
+		interfaces := b.Interfaces[:0]
+		for _, it := range b.Interfaces {
+			if keep(it.Name) {
+				interfaces = append(interfaces, it)
+			}
+		}
+		b.Interfaces = interfaces
+
+		models := b.Models[:0]
+		for _, it := range b.Models {
+			if keep(it.Name) {
+				models = append(models, it)
+			}
+		}
+		b.Models = models
+
+		enums := b.Enums[:0]
+		for _, it := range b.Enums {
+			if keep(it.Name) {
+				enums = append(enums, it)
+			}
+		}
+		b.Enums = enums
+
+		scalars := b.Scalars[:0]
+		for _, it := range b.Scalars {
+			if keep(it) {
+				scalars = append(scalars, it)
+			}
+		}
+		b.Scalars = scalars
+
+		return b
+	}
+}
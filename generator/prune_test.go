@@ -0,0 +1,150 @@
+package generator_test
+
+import (
+	"sort"
+	"testing"
+
+	gqlgenconfig "github.com/99designs/gqlgen/codegen/config"
+	"github.com/99designs/gqlgen/plugin/modelgen"
+	"github.com/Yamashou/gqlgenc/config"
+	"github.com/Yamashou/gqlgenc/generator"
+	"github.com/google/go-cmp/cmp"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+	"github.com/vektah/gqlparser/v2/validator"
+)
+
+const pruneSchema = `
+type Query {
+  user(id: ID!): User
+  search(filter: Filter): [Result!]!
+}
+
+type User {
+  id: ID!
+  role: Role
+  createdAt: DateTime
+  posts: [Post!]!
+}
+
+type Post {
+  title: String!
+}
+
+union Result = User | Post
+
+enum Role { ADMIN USER }
+
+enum Unreachable { A }
+
+input Filter {
+  role: Role
+  page: Page
+}
+
+input Page {
+  first: Int
+}
+
+scalar DateTime
+`
+
+func TestUsedTypes(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{
+			name:  "selected enums and scalars",
+			query: `query { user(id: "1") { role createdAt posts { title } } }`,
+			want:  []string{"DateTime", "Role", "String"},
+		},
+		{
+			name:  "input objects of variables",
+			query: `query ($filter: Filter) { search(filter: $filter) { __typename } }`,
+			want:  []string{"Filter", "Int", "Page", "Role", "String"},
+		},
+		{
+			name:  "fragments",
+			query: `query { user(id: "1") { ...UserFields } } fragment UserFields on User { role }`,
+			want:  []string{"Role"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := loadPruneSchema(t)
+			queryDocument, err := parser.ParseQuery(&ast.Source{Name: "query.graphql", Input: tt.query})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if errs := validator.Validate(schema, queryDocument); errs != nil {
+				t.Fatal(errs)
+			}
+
+			var got []string
+			for name := range generator.UsedTypes(schema, queryDocument) {
+				got = append(got, name)
+			}
+			sort.Strings(got)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("(-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPruneModels(t *testing.T) {
+	cfg := &config.Config{
+		GQLConfig: &gqlgenconfig.Config{
+			Models: gqlgenconfig.TypeMap{
+				"User":     {Model: gqlgenconfig.StringList{"example.com/gen.User"}},
+				"Role":     {Model: gqlgenconfig.StringList{"example.com/gen.Role"}},
+				"DateTime": {Model: gqlgenconfig.StringList{"time.Time"}},
+			},
+		},
+	}
+	b := &modelgen.ModelBuild{
+		Interfaces: []*modelgen.Interface{{Name: "Node"}},
+		Models:     []*modelgen.Object{{Name: "User"}, {Name: "Filter"}},
+		Enums:      []*modelgen.Enum{{Name: "Role"}, {Name: "Unreachable"}},
+		Scalars:    []string{"DateTime", "Money"},
+	}
+
+	b = generator.PruneModels(cfg, map[string]bool{"Filter": true, "Role": true, "DateTime": true})(b)
+
+	var got []string
+	for _, it := range b.Interfaces {
+		got = append(got, it.Name)
+	}
+	for _, it := range b.Models {
+		got = append(got, it.Name)
+	}
+	for _, it := range b.Enums {
+		got = append(got, it.Name)
+	}
+	got = append(got, b.Scalars...)
+	if diff := cmp.Diff([]string{"Filter", "Role", "DateTime"}, got); diff != "" {
+		t.Errorf("models (-want +got):\n%s", diff)
+	}
+
+	var bound []string
+	for name := range cfg.GQLConfig.Models {
+		bound = append(bound, name)
+	}
+	sort.Strings(bound)
+	if diff := cmp.Diff([]string{"DateTime", "Role"}, bound); diff != "" {
+		t.Errorf("the models left out must be unbound (-want +got):\n%s", diff)
+	}
+}
+
+func loadPruneSchema(t *testing.T) *ast.Schema {
+	t.Helper()
+	schema, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: pruneSchema})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return schema
+}
@@ -0,0 +1,96 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/99designs/gqlgen/codegen/config"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// fallbackScalarModel is bound to the custom scalars which have no model and are not known,
+// as most of them are serialized as strings.
+const fallbackScalarModel = "github.com/99designs/gqlgen/graphql.String"
+
+// scalarModels are the Go types bound by name to common custom scalars which have no model.
+// The first Go type whose package the module can load is bound, since a package may not be required by the module.
+var scalarModels = map[string][]string{
+	"DateTime":   {"time.Time"},
+	"Timestamp":  {"time.Time"},
+	"Date":       {fallbackScalarModel},
+	"LocalDate":  {fallbackScalarModel},
+	"LocalTime":  {fallbackScalarModel},
+	"UUID":       {"github.com/google/uuid.UUID", fallbackScalarModel},
+	"JSON":       {"encoding/json.RawMessage"},
+	"JSONObject": {"encoding/json.RawMessage"},
+	"URI":        {fallbackScalarModel},
+	"URL":        {fallbackScalarModel},
+	"BigInt":     {"github.com/Yamashou/gqlgenc/graphqljson.BigInt"},
+	"Long":       {"github.com/99designs/gqlgen/graphql.Int64"},
+}
+
+// specifiedByModels are the Go types bound by the URL of @specifiedBy, which takes precedence over the name.
+var specifiedByModels = map[string][]string{
+	"https://scalars.graphql.org/andimarek/date-time":                                  {"time.Time"},
+	"https://scalars.graphql.org/andimarek/local-date":                                 {fallbackScalarModel},
+	"https://tools.ietf.org/html/rfc4122":                                              {"github.com/google/uuid.UUID", fallbackScalarModel},
+	"https://tools.ietf.org/html/rfc3986":                                              {fallbackScalarModel},
+	"https://www.ecma-international.org/publications-and-standards/standards/ecma-404": {"encoding/json.RawMessage"},
+}
+
+// bindScalars binds the custom scalars of the schema which have no model to the Go types of scalarModels
+// and specifiedByModels, or else to fallbackScalarModel with a warning if the scalar is used.
+// The packages of gqlgen must be initialized to tell which Go types the module can load.
+func bindScalars(cfg *config.Config, used map[string]bool) []string {
+	names := make([]string, 0, len(cfg.Schema.Types))
+	for name, definition := range cfg.Schema.Types {
+		if definition.Kind == ast.Scalar && !cfg.Models.Exists(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var warnings []string
+	for _, name := range names {
+		candidates, ok := specifiedByModels[strings.TrimSuffix(specifiedBy(cfg.Schema.Types[name]), "/")]
+		if !ok {
+			candidates, ok = scalarModels[name]
+		}
+		if !ok {
+			if used[name] {
+				warnings = append(warnings, fmt.Sprintf("scalar %s has no model and is bound to string, add it to models to bind it to another Go type", name))
+			}
+			candidates = []string{fallbackScalarModel}
+		}
+		cfg.Models.Add(name, loadableModel(cfg, candidates))
+	}
+
+	return warnings
+}
+
+// loadableModel returns the first of the Go types whose package the module can load, or else the last.
+func loadableModel(cfg *config.Config, candidates []string) string {
+	for _, candidate := range candidates[:len(candidates)-1] {
+		pkg := cfg.Packages.Load(candidate[:strings.LastIndex(candidate, ".")])
+		if pkg != nil && len(pkg.Errors) == 0 {
+			return candidate
+		}
+	}
+
+	return candidates[len(candidates)-1]
+}
+
+// specifiedBy returns the URL of @specifiedBy of the scalar, or "" if there is none.
+func specifiedBy(definition *ast.Definition) string {
+	directive := definition.Directives.ForName("specifiedBy")
+	if directive == nil {
+		return ""
+	}
+	argument := directive.Arguments.ForName("url")
+	if argument == nil || argument.Value == nil {
+		return ""
+	}
+
+	return argument.Value.Raw
+}
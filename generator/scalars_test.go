@@ -0,0 +1,91 @@
+package generator_test
+
+import (
+	"testing"
+
+	"github.com/99designs/gqlgen/codegen/config"
+	"github.com/Yamashou/gqlgenc/generator"
+	"github.com/google/go-cmp/cmp"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestBindScalars(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		used   map[string]bool
+		// scalarModels replace the Go types bound by name during the test
+		scalarModels map[string][]string
+		want         map[string]string
+		wantWarnings []string
+	}{
+		{
+			name:   "by name",
+			schema: `scalar DateTime scalar UUID`,
+			want: map[string]string{
+				"DateTime": "time.Time",
+				"UUID":     "github.com/google/uuid.UUID",
+			},
+		},
+		{
+			name:   "@specifiedBy takes precedence over the name",
+			schema: `scalar DateTime @specifiedBy(url: "https://tools.ietf.org/html/rfc3986")`,
+			want:   map[string]string{"DateTime": generator.FallbackScalarModel},
+		},
+		{
+			name:   "@specifiedBy with a trailing slash",
+			schema: `scalar Instant @specifiedBy(url: "https://scalars.graphql.org/andimarek/date-time/")`,
+			want:   map[string]string{"Instant": "time.Time"},
+		},
+		{
+			name:         "package the module can not load",
+			schema:       `scalar UUID`,
+			scalarModels: map[string][]string{"UUID": {"example.com/missing/uuid.UUID", generator.FallbackScalarModel}},
+			want:         map[string]string{"UUID": generator.FallbackScalarModel},
+		},
+		{
+			name:         "unknown scalar the operations use",
+			schema:       `scalar Money`,
+			used:         map[string]bool{"Money": true},
+			want:         map[string]string{"Money": generator.FallbackScalarModel},
+			wantWarnings: []string{"scalar Money has no model and is bound to string, add it to models to bind it to another Go type"},
+		},
+		{
+			name:   "unknown scalar the operations do not use",
+			schema: `scalar Money`,
+			want:   map[string]string{"Money": generator.FallbackScalarModel},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, candidates := range tt.scalarModels {
+				original := generator.ScalarModels[name]
+				generator.ScalarModels[name] = candidates
+				defer func(name string) { generator.ScalarModels[name] = original }(name)
+			}
+
+			schema, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: tt.schema + "\ntype Query { id: ID }"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			cfg := config.DefaultConfig()
+			cfg.Schema = schema
+			if err := cfg.Init(); err != nil {
+				t.Fatal(err)
+			}
+
+			warnings := generator.BindScalars(cfg, tt.used)
+			if diff := cmp.Diff(tt.wantWarnings, warnings); diff != "" {
+				t.Errorf("warnings (-want +got):\n%s", diff)
+			}
+			got := make(map[string]string, len(tt.want))
+			for name := range tt.want {
+				got[name] = cfg.Models[name].Model[0]
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("models (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -1,15 +1,24 @@
 module github.com/Yamashou/gqlgenc
 
-go 1.14
+go 1.24.0
 
 require (
-	github.com/99designs/gqlgen v0.13.0
-	github.com/agnivade/levenshtein v1.1.0 // indirect
-	github.com/google/go-cmp v0.5.2
+	github.com/99designs/gqlgen v0.17.45
+	github.com/google/go-cmp v0.6.0
 	github.com/mailru/easyjson v0.7.6
-	github.com/pkg/errors v0.9.1 // indirect
-	github.com/vektah/gqlparser/v2 v2.1.0
-	golang.org/x/tools v0.0.0-20200827163409-021d7c6f1ec3 // indirect
+	github.com/vektah/gqlparser/v2 v2.5.11
+	golang.org/x/tools v0.40.0
 	golang.org/x/xerrors v0.0.0-20200804184101-5ec99f83aff1
-	gopkg.in/yaml.v2 v2.3.0
+	gopkg.in/yaml.v2 v2.4.0
+)
+
+require (
+	github.com/agnivade/levenshtein v1.1.1 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/josharian/intern v1.0.0 // indirect
+	github.com/sosodev/duration v1.2.0 // indirect
+	golang.org/x/mod v0.31.0 // indirect
+	golang.org/x/sync v0.19.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
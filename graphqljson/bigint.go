@@ -0,0 +1,49 @@
+package graphqljson
+
+import (
+	"bytes"
+	"math/big"
+	"strconv"
+
+	"golang.org/x/xerrors"
+)
+
+// BigInt is an integer of any size. Servers serialize the BigInt scalar either as a JSON number,
+// or as a JSON string which keeps its precision in JavaScript, so it is decoded from both.
+type BigInt struct {
+	big.Int
+	// Quoted is whether the integer is encoded as a JSON string, as it is when decoded from one.
+	Quoted bool
+}
+
+func (b *BigInt) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	text := data
+	b.Quoted = len(data) > 0 && data[0] == '"'
+	if b.Quoted {
+		unquoted, err := strconv.Unquote(string(data))
+		if err != nil {
+			return xerrors.Errorf("invalid BigInt %s: %w", data, err)
+		}
+		text = []byte(unquoted)
+	}
+	if _, ok := b.Int.SetString(string(text), 10); !ok {
+		return xerrors.Errorf("invalid BigInt %s", data)
+	}
+
+	return nil
+}
+
+func (b *BigInt) MarshalJSON() ([]byte, error) {
+	if b == nil {
+		return []byte("null"), nil
+	}
+	if b.Quoted {
+		return []byte(strconv.Quote(b.Int.String())), nil
+	}
+
+	return []byte(b.Int.String()), nil
+}
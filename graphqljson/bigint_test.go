@@ -0,0 +1,40 @@
+package graphqljson_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Yamashou/gqlgenc/graphqljson"
+)
+
+func TestBigInt(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "number", data: `{"amount":123456789012345678901234567890}`},
+		{name: "string", data: `{"amount":"123456789012345678901234567890"}`},
+		{name: "null", data: `{"amount":null}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got struct {
+				Amount *graphqljson.BigInt `json:"amount"`
+			}
+			if err := graphqljson.UnmarshalData([]byte(tt.data), &got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Amount != nil && got.Amount.String() != "123456789012345678901234567890" {
+				t.Errorf("got %s", got.Amount.String())
+			}
+
+			b, err := json.Marshal(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != tt.data {
+				t.Errorf("want %s, got %s", tt.data, b)
+			}
+		})
+	}
+}
@@ -239,6 +239,26 @@ func (d *Decoder) decode() error {
 			d.popAllVs()
 
 		case json.Delim:
+			if (tok == '{' || tok == '[') && d.rawTargets() {
+				// Scalar holding an object or an array, such as JSON.
+				raw, err := d.readRaw(tok)
+				if err != nil {
+					return xerrors.Errorf(": %w", err)
+				}
+				for i := range d.vs {
+					v := d.vs[i][len(d.vs[i])-1]
+					if !v.IsValid() {
+						continue
+					}
+					if err := json.Unmarshal(raw, v.Addr().Interface()); err != nil {
+						return xerrors.Errorf(": %w", err)
+					}
+				}
+				d.popAllVs()
+
+				continue
+			}
+
 			switch tok {
 			case '{':
 				// Start of object.
@@ -309,6 +329,86 @@ func (d *Decoder) decode() error {
 	return nil
 }
 
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// rawTargets reports whether the values on top of d.vs take an object or an array as a whole,
+// as maps, interfaces and slices unmarshaling JSON such as json.RawMessage do.
+func (d *Decoder) rawTargets() bool {
+	found := false
+	for i := range d.vs {
+		v := d.vs[i][len(d.vs[i])-1]
+		if !v.IsValid() {
+			continue
+		}
+		t := v.Type()
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		switch t.Kind() {
+		case reflect.Map, reflect.Interface:
+		case reflect.Slice, reflect.Array:
+			if !reflect.PtrTo(t).Implements(jsonUnmarshalerType) {
+				return false
+			}
+		default:
+			return false
+		}
+		found = true
+	}
+
+	return found
+}
+
+// readRaw reads the rest of the object or array starting with the delimiter, and returns it as JSON.
+func (d *Decoder) readRaw(start json.Delim) (json.RawMessage, error) {
+	var buf bytes.Buffer
+	buf.WriteString(start.String())
+	// the objects and arrays being read, with the number of keys and values read in each
+	delims := []json.Delim{start}
+	counts := []int{0}
+	for len(delims) > 0 {
+		tok, err := d.jsonDecoder.Token()
+		if err == io.EOF {
+			return nil, xerrors.New("unexpected end of JSON input")
+		} else if err != nil {
+			return nil, xerrors.Errorf(": %w", err)
+		}
+
+		if tok == json.Delim('}') || tok == json.Delim(']') {
+			buf.WriteString(tok.(json.Delim).String())
+			delims, counts = delims[:len(delims)-1], counts[:len(counts)-1]
+			if len(counts) > 0 {
+				counts[len(counts)-1]++
+			}
+
+			continue
+		}
+
+		last := len(delims) - 1
+		switch n := counts[last]; {
+		case delims[last] == '{' && n%2 == 1:
+			buf.WriteByte(':')
+		case n > 0:
+			buf.WriteByte(',')
+		}
+
+		if delim, ok := tok.(json.Delim); ok {
+			buf.WriteString(delim.String())
+			delims, counts = append(delims, delim), append(counts, 0)
+
+			continue
+		}
+		b, err := json.Marshal(tok)
+		if err != nil {
+			return nil, xerrors.Errorf(": %w", err)
+		}
+		buf.Write(b)
+		counts[last]++
+	}
+
+	return buf.Bytes(), nil
+}
+
 // pushState pushes a new parse state s onto the stack.
 func (d *Decoder) pushState(s json.Delim) {
 	d.parseState = append(d.parseState, s)
@@ -1,6 +1,7 @@
 package graphqljson_test
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -41,6 +42,38 @@ func TestUnmarshalGraphQL(t *testing.T) {
 	}
 }
 
+func TestUnmarshalGraphQL_rawScalars(t *testing.T) {
+	type query struct {
+		Settings json.RawMessage
+		Tags     []json.RawMessage
+		Meta     map[string]interface{}
+		Any      interface{}
+		Nullable *json.RawMessage
+	}
+	var got query
+	err := graphqljson.UnmarshalData([]byte(`{
+		"settings": {"theme": "dark", "sizes": [1, 2.5, {"x": null}], "on": true},
+		"tags": [["a", "b"], {"c": 1}],
+		"meta": {"count": 3},
+		"any": [{"a": "<b>"}],
+		"nullable": {}
+	}`), &got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nullable := json.RawMessage(`{}`)
+	want := query{
+		Settings: json.RawMessage(`{"theme":"dark","sizes":[1,2.5,{"x":null}],"on":true}`),
+		Tags:     []json.RawMessage{json.RawMessage(`["a","b"]`), json.RawMessage(`{"c":1}`)},
+		Meta:     map[string]interface{}{"count": float64(3)},
+		Any:      []interface{}{map[string]interface{}{"a": "<b>"}},
+		Nullable: &nullable,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error(diff)
+	}
+}
+
 func TestUnmarshalGraphQL_graphqlTag(t *testing.T) {
 	type query struct {
 		Foo string `graphql:"baz"`
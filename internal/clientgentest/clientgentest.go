@@ -0,0 +1,291 @@
+// Package clientgentest runs gqlgenc offline on a directory holding a schema, query files and a config,
+// and compares the generated files with golden files.
+//
+// A test case directory looks like
+//
+//	.gqlgenc.yml
+//	schema.graphql
+//	query/*.graphql
+//	golden/gen/client.go.golden
+//
+// where the golden files mirror the generated files with the .golden suffix.
+// A test case whose generation must fail has golden/error.golden holding the output of gqlgenc instead.
+// Run the tests with -update to write the golden files.
+package clientgentest
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/Yamashou/gqlgenc/clientgen"
+	"github.com/Yamashou/gqlgenc/config"
+	"github.com/google/go-cmp/cmp"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+const (
+	configFilename = ".gqlgenc.yml"
+	goldenDir      = "golden"
+	goldenSuffix   = ".golden"
+	errorGolden    = "error.golden"
+	// generateTimes is how many times the code is generated to assert that the output is reproducible
+	generateTimes = 2
+)
+
+// Run generates the code for the test case directory in a temporary module, and checks that
+// generation is reproducible, that the generated files match the golden files, that gqlgenc --check
+// finds them up to date and that they compile.
+func Run(t *testing.T, dir string) {
+	t.Helper()
+
+	root, err := repositoryRoot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	module := tempModule(t, root, dir)
+
+	errorFilename := filepath.Join(dir, goldenDir, errorGolden)
+	if _, err := os.Stat(errorFilename); err == nil {
+		out, err := generateOutput(module)
+		if err == nil {
+			t.Fatalf("generation succeeded, want the error of %s", errorFilename)
+		}
+		AssertGolden(t, errorFilename, string(out))
+
+		return
+	}
+
+	var outputs []string
+	var first map[string]string
+	for i := 0; i < generateTimes; i++ {
+		generate(t, module)
+
+		// the files of a split client are known only after generation
+		outputs = outputFilenames(t, module)
+		generated := readFiles(t, module, outputs)
+		if first == nil {
+			first = generated
+
+			continue
+		}
+		if diff := cmp.Diff(first, generated); diff != "" {
+			t.Fatalf("generation %d differs from the first (-first +got):\n%s", i+1, diff)
+		}
+	}
+
+	for _, output := range outputs {
+		AssertGolden(t, filepath.Join(dir, goldenDir, filepath.FromSlash(output)+goldenSuffix), first[output])
+	}
+
+	// --check generates again, which must find the files on disk up to date
+	if out, err := command(module, "go", "run", "github.com/Yamashou/gqlgenc", "--check"); err != nil {
+		t.Fatalf("gqlgenc --check failed: %v\n%s", err, out)
+	}
+
+	// and report a file edited by hand while leaving it as it is
+	edited := filepath.Join(module, filepath.FromSlash(outputs[0]))
+	if err := ioutil.WriteFile(edited, []byte(first[outputs[0]]+"// edited\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := command(module, "go", "run", "github.com/Yamashou/gqlgenc", "--check"); err == nil || !strings.Contains(string(out), "-// edited") {
+		t.Fatalf("gqlgenc --check did not report the edited %s: %v\n%s", outputs[0], err, out)
+	}
+	if got := readFiles(t, module, outputs[:1]); got[outputs[0]] != first[outputs[0]]+"// edited\n" {
+		t.Fatalf("gqlgenc --check changed %s", outputs[0])
+	}
+	if err := ioutil.WriteFile(edited, []byte(first[outputs[0]]), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	goCommand(t, module, "build", "./...")
+}
+
+// AssertGolden compares got with the golden file, or writes the golden file with -update.
+func AssertGolden(t *testing.T, filename, got string) {
+	t.Helper()
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filename, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		return
+	}
+
+	want, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("%v, run the test with -update to write it", err)
+	}
+	if diff := cmp.Diff(string(want), got); diff != "" {
+		t.Errorf("%s mismatch (-want +got):\n%s", filename, diff)
+	}
+}
+
+// repositoryRoot returns the root directory of gqlgenc, the parent of the directory of this file.
+func repositoryRoot() (string, error) {
+	_, filename, _, _ := runtime.Caller(0)
+
+	return filepath.Abs(filepath.Join(filepath.Dir(filename), "..", ".."))
+}
+
+// tempModule copies the test case into a temporary module which uses gqlgenc of the repository.
+func tempModule(t *testing.T, root, dir string) string {
+	t.Helper()
+
+	module, err := ioutil.TempDir("", "clientgentest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(module) })
+	// resolve symlinks to compare with the absolute paths of the config
+	module, err = filepath.EvalSymlinks(module)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if rel == goldenDir {
+				return filepath.SkipDir
+			}
+
+			return os.MkdirAll(filepath.Join(module, rel), 0o755)
+		}
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		return ioutil.WriteFile(filepath.Join(module, rel), content, 0o644)
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the module requires the same versions as gqlgenc, and gqlgenc itself from the repository
+	goMod, err := ioutil.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	goMod = regexp.MustCompile(`(?m)^module .*$`).ReplaceAll(goMod, []byte("module example.com/clientgentest"))
+	goMod = append(goMod, []byte("\nrequire github.com/Yamashou/gqlgenc v0.0.0\n\nreplace github.com/Yamashou/gqlgenc => "+filepath.ToSlash(root)+"\n")...)
+	if err := ioutil.WriteFile(filepath.Join(module, "go.mod"), goMod, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	goSum, err := ioutil.ReadFile(filepath.Join(root, "go.sum"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(module, "go.sum"), goSum, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return module
+}
+
+// outputFilenames returns the slash separated paths of the generated files relative to the module.
+func outputFilenames(t *testing.T, module string) []string {
+	t.Helper()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(module); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd) //nolint:errcheck
+
+	cfg, err := config.LoadConfig(configFilename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	filenames := []string{cfg.Client.Filename}
+	if cfg.Model.IsDefined() {
+		filenames = append(filenames, cfg.Model.Filename)
+	}
+	splitFilenames, err := clientgen.SplitFiles(cfg.Client.Filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	filenames = append(filenames, splitFilenames...)
+
+	outputs := make([]string, 0, len(filenames))
+	for _, filename := range filenames {
+		abs, err := filepath.Abs(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rel, err := filepath.Rel(module, abs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		outputs = append(outputs, filepath.ToSlash(rel))
+	}
+
+	return outputs
+}
+
+// generate runs gqlgenc in a separate process, since generation loads packages with go/packages
+// which exits the process on errors.
+func generate(t *testing.T, module string) {
+	t.Helper()
+
+	if out, err := generateOutput(module); err != nil {
+		t.Fatalf("generation failed: %v\n%s", err, out)
+	}
+}
+
+// generateOutput runs gqlgenc and returns its output.
+func generateOutput(module string) ([]byte, error) {
+	return command(module, "go", "run", "github.com/Yamashou/gqlgenc")
+}
+
+func goCommand(t *testing.T, module string, args ...string) {
+	t.Helper()
+
+	if out, err := command(module, "go", args...); err != nil {
+		t.Fatalf("go %s failed: %v\n%s", strings.Join(args, " "), err, out)
+	}
+}
+
+func command(dir, name string, args ...string) ([]byte, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOPROXY=off")
+
+	return cmd.CombinedOutput()
+}
+
+func readFiles(t *testing.T, module string, filenames []string) map[string]string {
+	t.Helper()
+
+	files := make(map[string]string, len(filenames))
+	for _, filename := range filenames {
+		content, err := ioutil.ReadFile(filepath.Join(module, filepath.FromSlash(filename)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[filename] = string(content)
+	}
+
+	return files
+}
@@ -0,0 +1,227 @@
+// Package unified computes line based diffs in the unified format.
+package unified
+
+import (
+	"fmt"
+	"strings"
+)
+
+const contextLines = 3
+
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+type op struct {
+	kind opKind
+	line string
+}
+
+// Diff returns the unified diff from a to b, or an empty string if they are equal.
+func Diff(aName, bName, a, b string) string {
+	if a == b {
+		return ""
+	}
+
+	ops := diffLines(splitLines(a), splitLines(b))
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "--- %s\n+++ %s\n", aName, bName)
+	for _, h := range hunks(ops) {
+		h.write(&buf, ops)
+	}
+
+	return buf.String()
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// diffLines implements the greedy algorithm from Myers' "An O(ND) Difference Algorithm and Its Variations".
+func diffLines(a, b []string) []op {
+	n, m := len(a), len(b)
+	max := n + m
+	v := make([]int, 2*max+2)
+	var trace [][]int
+
+	for d := 0; d <= max; d++ {
+		snapshot := make([]int, len(v))
+		copy(snapshot, v)
+		trace = append(trace, snapshot)
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[max+k-1] < v[max+k+1]) {
+				x = v[max+k+1]
+			} else {
+				x = v[max+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[max+k] = x
+
+			if x >= n && y >= m {
+				return backtrack(a, b, trace, d)
+			}
+		}
+	}
+
+	return nil
+}
+
+func backtrack(a, b []string, trace [][]int, d int) []op {
+	max := len(a) + len(b)
+	x, y := len(a), len(b)
+	var ops []op
+
+	for ; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && v[max+k-1] < v[max+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[max+prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			ops = append(ops, op{kind: opEqual, line: a[x]})
+		}
+		if d > 0 {
+			if x == prevX {
+				y--
+				ops = append(ops, op{kind: opInsert, line: b[y]})
+			} else {
+				x--
+				ops = append(ops, op{kind: opDelete, line: a[x]})
+			}
+		}
+	}
+
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+
+	return ops
+}
+
+type hunk struct {
+	start, end     int // range in ops
+	aStart, bStart int // 0 origin line numbers
+}
+
+func hunks(ops []op) []*hunk {
+	var result []*hunk
+	var current *hunk
+	aLine, bLine := 0, 0
+
+	for i, o := range ops {
+		if o.kind != opEqual {
+			start := i - contextLines
+			if start < 0 {
+				start = 0
+			}
+			if current != nil && start <= current.end {
+				current.end = i + 1
+			} else {
+				current = &hunk{start: start, end: i + 1}
+				current.aStart, current.bStart = aLine, bLine
+				for j := i - 1; j >= start; j-- {
+					current.aStart--
+					current.bStart--
+				}
+				result = append(result, current)
+			}
+		}
+
+		switch o.kind {
+		case opEqual:
+			aLine++
+			bLine++
+		case opDelete:
+			aLine++
+		case opInsert:
+			bLine++
+		}
+	}
+
+	for _, h := range result {
+		h.end += contextLines
+		if h.end > len(ops) {
+			h.end = len(ops)
+		}
+	}
+
+	// trailing context may reach the next hunk
+	merged := make([]*hunk, 0, len(result))
+	for _, h := range result {
+		if len(merged) > 0 && h.start <= merged[len(merged)-1].end {
+			merged[len(merged)-1].end = h.end
+
+			continue
+		}
+		merged = append(merged, h)
+	}
+
+	return merged
+}
+
+func (h *hunk) write(buf *strings.Builder, ops []op) {
+	aCount, bCount := 0, 0
+	for _, o := range ops[h.start:h.end] {
+		if o.kind != opInsert {
+			aCount++
+		}
+		if o.kind != opDelete {
+			bCount++
+		}
+	}
+
+	fmt.Fprintf(buf, "@@ -%s +%s @@\n", lineRange(h.aStart, aCount), lineRange(h.bStart, bCount))
+	for _, o := range ops[h.start:h.end] {
+		switch o.kind {
+		case opEqual:
+			buf.WriteString(" ")
+		case opDelete:
+			buf.WriteString("-")
+		case opInsert:
+			buf.WriteString("+")
+		}
+		buf.WriteString(o.line)
+		if !strings.HasSuffix(o.line, "\n") {
+			buf.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+func lineRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
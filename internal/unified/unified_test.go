@@ -0,0 +1,37 @@
+package unified_test
+
+import (
+	"testing"
+
+	"github.com/Yamashou/gqlgenc/internal/unified"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDiff(t *testing.T) {
+	a := "a\nb\nc\nd\ne\nf\ng\nh\n"
+	b := "a\nb\nc\nD\ne\nf\ng\nh\ni\n"
+
+	want := `--- old
++++ new
+@@ -1,8 +1,9 @@
+ a
+ b
+ c
+-d
++D
+ e
+ f
+ g
+ h
++i
+`
+	if diff := cmp.Diff(want, unified.Diff("old", "new", a, b)); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestDiff_equal(t *testing.T) {
+	if got := unified.Diff("old", "new", "a\n", "a\n"); got != "" {
+		t.Errorf("want empty diff, got %q", got)
+	}
+}
@@ -0,0 +1,25 @@
+package introspection
+
+import (
+	"fmt"
+
+	"golang.org/x/xerrors"
+)
+
+// ErrTypeRefTruncated is returned when a type is wrapped in more lists and non-nulls than the introspection query asked for.
+var ErrTypeRefTruncated = xerrors.New("type is nested deeper than the introspection query asked for")
+
+// SchemaError is an error in the introspection result.
+type SchemaError struct {
+	// Coordinate is the schema coordinate such as User, User.name, User.friends(first:) or @include(if:)
+	Coordinate string
+	Err        error
+}
+
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Coordinate, e.Err)
+}
+
+func (e *SchemaError) Unwrap() error {
+	return e.Err
+}
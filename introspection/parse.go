@@ -4,56 +4,97 @@ import (
 	"fmt"
 
 	"github.com/vektah/gqlparser/v2/ast"
+	"golang.org/x/xerrors"
 )
 
-func ParseIntrospectionQuery(query Query) *ast.SchemaDocument {
+func ParseIntrospectionQuery(query Query) (*ast.SchemaDocument, error) {
 	var doc ast.SchemaDocument
+	for i, typeVale := range query.Schema.Types {
+		if typeVale == nil || typeVale.Name == nil {
+			return nil, xerrors.Errorf("types[%d]: type has no name", i)
+		}
+	}
 	typeMap := query.Schema.Types.NameMap()
 
 	doc.Schema = append(doc.Schema, parseSchemaDefinition(query, typeMap))
 
-	for _, typeVale := range typeMap {
-		doc.Definitions = append(doc.Definitions, parseTypeSystemDefinition(typeVale))
+	for _, typeVale := range query.Schema.Types {
+		def, err := parseTypeSystemDefinition(typeVale)
+		if err != nil {
+			return nil, err
+		}
+		doc.Definitions = append(doc.Definitions, def)
 	}
 
 	for _, directiveValue := range query.Schema.Directives {
-		doc.Directives = append(doc.Directives, parseDirectiveDefinition(directiveValue))
+		def, err := parseDirectiveDefinition(directiveValue)
+		if err != nil {
+			return nil, err
+		}
+		doc.Directives = append(doc.Directives, def)
 	}
 
-	return &doc
+	// deprecation, specifiedByURL and isOneOf are expressed as directives on the ast,
+	// so they must be defined even if the server omits them from the introspection result.
+	for _, def := range []*ast.DirectiveDefinition{
+		deprecatedDirectiveDefinition(),
+		specifiedByDirectiveDefinition(),
+		oneOfDirectiveDefinition(),
+	} {
+		if doc.Directives.ForName(def.Name) == nil {
+			doc.Directives = append(doc.Directives, def)
+		}
+	}
+
+	return &doc, nil
 }
 
 func parseSchemaDefinition(query Query, typeMap map[string]*FullType) *ast.SchemaDefinition {
-	def := ast.SchemaDefinition{}
+	def := ast.SchemaDefinition{
+		Description: pointerString(query.Schema.Description),
+	}
 
-	def.OperationTypes = append(def.OperationTypes,
-		parseOperationTypeDefinitionForQuery(typeMap[*query.Schema.QueryType.Name]),
-		parseOperationTypeDefinitionForMutation(typeMap[*query.Schema.MutationType.Name]),
-	)
+	// only the query root is mandatory, and each root may have any name
+	if fullType := rootType(typeMap, query.Schema.QueryType.Name); fullType != nil {
+		def.OperationTypes = append(def.OperationTypes, parseOperationTypeDefinition(ast.Query, fullType))
+	}
+	if query.Schema.MutationType != nil {
+		if fullType := rootType(typeMap, query.Schema.MutationType.Name); fullType != nil {
+			def.OperationTypes = append(def.OperationTypes, parseOperationTypeDefinition(ast.Mutation, fullType))
+		}
+	}
+	if query.Schema.SubscriptionType != nil {
+		if fullType := rootType(typeMap, query.Schema.SubscriptionType.Name); fullType != nil {
+			def.OperationTypes = append(def.OperationTypes, parseOperationTypeDefinition(ast.Subscription, fullType))
+		}
+	}
 
 	return &def
 }
 
-func parseOperationTypeDefinitionForQuery(fullType *FullType) *ast.OperationTypeDefinition {
-	var op ast.OperationTypeDefinition
-	op.Operation = ast.Query
-	op.Type = *fullType.Name
+func rootType(typeMap map[string]*FullType, name *string) *FullType {
+	if name == nil {
+		return nil
+	}
 
-	return &op
+	return typeMap[*name]
 }
 
-func parseOperationTypeDefinitionForMutation(fullType *FullType) *ast.OperationTypeDefinition {
+func parseOperationTypeDefinition(operation ast.Operation, fullType *FullType) *ast.OperationTypeDefinition {
 	var op ast.OperationTypeDefinition
-	op.Operation = ast.Mutation
+	op.Operation = operation
 	op.Type = *fullType.Name
 
 	return &op
 }
 
-func parseDirectiveDefinition(directiveValue *DirectiveType) *ast.DirectiveDefinition {
+func parseDirectiveDefinition(directiveValue *DirectiveType) (*ast.DirectiveDefinition, error) {
 	args := make(ast.ArgumentDefinitionList, 0, len(directiveValue.Args))
 	for _, arg := range directiveValue.Args {
-		argumentDefinition := buildInputValue(arg)
+		argumentDefinition, err := buildInputValue(arg)
+		if err != nil {
+			return nil, &SchemaError{Coordinate: fmt.Sprintf("@%s(%s:)", directiveValue.Name, arg.Name), Err: err}
+		}
 		args = append(args, argumentDefinition)
 	}
 	locations := make([]ast.DirectiveLocation, 0, len(directiveValue.Locations))
@@ -62,20 +103,27 @@ func parseDirectiveDefinition(directiveValue *DirectiveType) *ast.DirectiveDefin
 	}
 
 	return &ast.DirectiveDefinition{
-		Description: pointerString(directiveValue.Description),
-		Name:        directiveValue.Name,
-		Arguments:   args,
-		Locations:   locations,
-	}
+		Description:  pointerString(directiveValue.Description),
+		Name:         directiveValue.Name,
+		Arguments:    args,
+		Locations:    locations,
+		IsRepeatable: directiveValue.IsRepeatable,
+	}, nil
 }
 
-func parseObjectFields(typeVale *FullType) ast.FieldList {
+func parseObjectFields(typeVale *FullType) (ast.FieldList, error) {
 	fieldList := make(ast.FieldList, 0, len(typeVale.Fields))
 	for _, field := range typeVale.Fields {
-		typ := getType(&field.Type)
+		typ, err := getType(&field.Type)
+		if err != nil {
+			return nil, &SchemaError{Coordinate: fmt.Sprintf("%s.%s", *typeVale.Name, field.Name), Err: err}
+		}
 		args := make(ast.ArgumentDefinitionList, 0, len(field.Args))
 		for _, arg := range field.Args {
-			argumentDefinition := buildInputValue(arg)
+			argumentDefinition, err := buildInputValue(arg)
+			if err != nil {
+				return nil, &SchemaError{Coordinate: fmt.Sprintf("%s.%s(%s:)", *typeVale.Name, field.Name, arg.Name), Err: err}
+			}
 			args = append(args, argumentDefinition)
 		}
 
@@ -84,30 +132,39 @@ func parseObjectFields(typeVale *FullType) ast.FieldList {
 			Name:        field.Name,
 			Arguments:   args,
 			Type:        typ,
+			Directives:  deprecatedDirectives(field.IsDeprecated, field.DeprecationReason),
 		}
 		fieldList = append(fieldList, fieldDefinition)
 	}
 
-	return fieldList
+	return fieldList, nil
 }
 
-func parseInputObjectFields(typeVale *FullType) ast.FieldList {
+func parseInputObjectFields(typeVale *FullType) (ast.FieldList, error) {
 	fieldList := make(ast.FieldList, 0, len(typeVale.InputFields))
 	for _, field := range typeVale.InputFields {
-		typ := getType(&field.Type)
+		inputValue, err := buildInputValue(field)
+		if err != nil {
+			return nil, &SchemaError{Coordinate: fmt.Sprintf("%s.%s", *typeVale.Name, field.Name), Err: err}
+		}
 		fieldDefinition := &ast.FieldDefinition{
-			Description: pointerString(field.Description),
-			Name:        field.Name,
-			Type:        typ,
+			Description:  inputValue.Description,
+			Name:         inputValue.Name,
+			DefaultValue: inputValue.DefaultValue,
+			Type:         inputValue.Type,
+			Directives:   inputValue.Directives,
 		}
 		fieldList = append(fieldList, fieldDefinition)
 	}
 
-	return fieldList
+	return fieldList, nil
 }
 
-func parseObjectTypeDefinition(typeVale *FullType) *ast.Definition {
-	fieldList := parseObjectFields(typeVale)
+func parseObjectTypeDefinition(typeVale *FullType) (*ast.Definition, error) {
+	fieldList, err := parseObjectFields(typeVale)
+	if err != nil {
+		return nil, err
+	}
 	interfaces := make([]string, 0, len(typeVale.Interfaces))
 	for _, intf := range typeVale.Interfaces {
 		interfaces = append(interfaces, pointerString(intf.Name))
@@ -118,6 +175,7 @@ func parseObjectTypeDefinition(typeVale *FullType) *ast.Definition {
 		enumValue := &ast.EnumValueDefinition{
 			Description: pointerString(enum.Description),
 			Name:        enum.Name,
+			Directives:  deprecatedDirectives(enum.IsDeprecated, enum.DeprecationReason),
 		}
 		enums = append(enums, enumValue)
 	}
@@ -131,11 +189,14 @@ func parseObjectTypeDefinition(typeVale *FullType) *ast.Definition {
 		EnumValues:  enums,
 		Position:    nil,
 		BuiltIn:     true,
-	}
+	}, nil
 }
 
-func parseInterfaceTypeDefinition(typeVale *FullType) *ast.Definition {
-	fieldList := parseObjectFields(typeVale)
+func parseInterfaceTypeDefinition(typeVale *FullType) (*ast.Definition, error) {
+	fieldList, err := parseObjectFields(typeVale)
+	if err != nil {
+		return nil, err
+	}
 	interfaces := make([]string, 0, len(typeVale.Interfaces))
 	for _, intf := range typeVale.Interfaces {
 		interfaces = append(interfaces, pointerString(intf.Name))
@@ -149,30 +210,42 @@ func parseInterfaceTypeDefinition(typeVale *FullType) *ast.Definition {
 		Fields:      fieldList,
 		Position:    nil,
 		BuiltIn:     true,
-	}
+	}, nil
 }
 
-func parseInputObjectTypeDefinition(typeVale *FullType) *ast.Definition {
-	fieldList := parseInputObjectFields(typeVale)
+func parseInputObjectTypeDefinition(typeVale *FullType) (*ast.Definition, error) {
+	fieldList, err := parseInputObjectFields(typeVale)
+	if err != nil {
+		return nil, err
+	}
 	interfaces := make([]string, 0, len(typeVale.Interfaces))
 	for _, intf := range typeVale.Interfaces {
 		interfaces = append(interfaces, pointerString(intf.Name))
 	}
 
+	var directives ast.DirectiveList
+	if typeVale.IsOneOf != nil && *typeVale.IsOneOf {
+		directives = append(directives, &ast.Directive{Name: oneOfDirectiveName})
+	}
+
 	return &ast.Definition{
 		Kind:        ast.InputObject,
 		Description: pointerString(typeVale.Description),
 		Name:        pointerString(typeVale.Name),
 		Interfaces:  interfaces,
 		Fields:      fieldList,
+		Directives:  directives,
 		Position:    nil,
 		BuiltIn:     true,
-	}
+	}, nil
 }
 
-func parseUnionTypeDefinition(typeVale *FullType) *ast.Definition {
+func parseUnionTypeDefinition(typeVale *FullType) (*ast.Definition, error) {
 	unions := make([]string, 0, len(typeVale.PossibleTypes))
 	for _, unionValue := range typeVale.PossibleTypes {
+		if unionValue.Name == nil {
+			return nil, &SchemaError{Coordinate: *typeVale.Name, Err: xerrors.New("possible type has no name")}
+		}
 		unions = append(unions, *unionValue.Name)
 	}
 
@@ -183,15 +256,16 @@ func parseUnionTypeDefinition(typeVale *FullType) *ast.Definition {
 		Types:       unions,
 		Position:    nil,
 		BuiltIn:     true,
-	}
+	}, nil
 }
 
-func parseEnumTypeDefinition(typeVale *FullType) *ast.Definition {
+func parseEnumTypeDefinition(typeVale *FullType) (*ast.Definition, error) {
 	enums := make(ast.EnumValueList, 0, len(typeVale.EnumValues))
 	for _, enum := range typeVale.EnumValues {
 		enumValue := &ast.EnumValueDefinition{
 			Description: pointerString(enum.Description),
 			Name:        enum.Name,
+			Directives:  deprecatedDirectives(enum.IsDeprecated, enum.DeprecationReason),
 		}
 		enums = append(enums, enumValue)
 	}
@@ -203,20 +277,37 @@ func parseEnumTypeDefinition(typeVale *FullType) *ast.Definition {
 		EnumValues:  enums,
 		Position:    nil,
 		BuiltIn:     true,
-	}
+	}, nil
 }
 
-func parseScalarTypeExtension(typeVale *FullType) *ast.Definition {
+func parseScalarTypeExtension(typeVale *FullType) (*ast.Definition, error) {
+	var directives ast.DirectiveList
+	if typeVale.SpecifiedByURL != nil {
+		directives = append(directives, &ast.Directive{
+			Name: specifiedByDirectiveName,
+			Arguments: ast.ArgumentList{
+				{
+					Name: "url",
+					Value: &ast.Value{
+						Raw:  *typeVale.SpecifiedByURL,
+						Kind: ast.StringValue,
+					},
+				},
+			},
+		})
+	}
+
 	return &ast.Definition{
 		Kind:        ast.Scalar,
 		Description: pointerString(typeVale.Description),
 		Name:        pointerString(typeVale.Name),
+		Directives:  directives,
 		Position:    nil,
 		BuiltIn:     true,
-	}
+	}, nil
 }
 
-func parseTypeSystemDefinition(typeVale *FullType) *ast.Definition {
+func parseTypeSystemDefinition(typeVale *FullType) (*ast.Definition, error) {
 	switch typeVale.Kind {
 	case TypeKindScalar:
 		return parseScalarTypeExtension(typeVale)
@@ -231,10 +322,82 @@ func parseTypeSystemDefinition(typeVale *FullType) *ast.Definition {
 	case TypeKindInputObject:
 		return parseInputObjectTypeDefinition(typeVale)
 	case TypeKindList, TypeKindNonNull:
-		panic(fmt.Sprintf("not match Kind: %s", typeVale.Kind))
+		return nil, &SchemaError{Coordinate: *typeVale.Name, Err: xerrors.Errorf("%s is not allowed as the kind of a named type", typeVale.Kind)}
 	}
 
-	panic(fmt.Sprintf("not match Kind: %s", typeVale.Kind))
+	return nil, &SchemaError{Coordinate: *typeVale.Name, Err: xerrors.Errorf("unknown kind %q", typeVale.Kind)}
+}
+
+const deprecatedDirectiveName = "deprecated"
+
+func deprecatedDirectiveDefinition() *ast.DirectiveDefinition {
+	return &ast.DirectiveDefinition{
+		Name: deprecatedDirectiveName,
+		Arguments: ast.ArgumentDefinitionList{
+			{
+				Name: "reason",
+				Type: ast.NamedType("String", nil),
+				DefaultValue: &ast.Value{
+					Raw:  "No longer supported",
+					Kind: ast.StringValue,
+				},
+			},
+		},
+		Locations: []ast.DirectiveLocation{
+			ast.LocationFieldDefinition,
+			ast.LocationArgumentDefinition,
+			ast.LocationInputFieldDefinition,
+			ast.LocationEnumValue,
+		},
+	}
+}
+
+const specifiedByDirectiveName = "specifiedBy"
+
+func specifiedByDirectiveDefinition() *ast.DirectiveDefinition {
+	return &ast.DirectiveDefinition{
+		Name: specifiedByDirectiveName,
+		Arguments: ast.ArgumentDefinitionList{
+			{
+				Name: "url",
+				Type: ast.NonNullNamedType("String", nil),
+			},
+		},
+		Locations: []ast.DirectiveLocation{ast.LocationScalar},
+	}
+}
+
+const oneOfDirectiveName = "oneOf"
+
+func oneOfDirectiveDefinition() *ast.DirectiveDefinition {
+	return &ast.DirectiveDefinition{
+		Name:      oneOfDirectiveName,
+		Locations: []ast.DirectiveLocation{ast.LocationInputObject},
+	}
+}
+
+func deprecatedDirectives(isDeprecated bool, reason *string) ast.DirectiveList {
+	if !isDeprecated {
+		return nil
+	}
+
+	var args ast.ArgumentList
+	if reason != nil {
+		args = append(args, &ast.Argument{
+			Name: "reason",
+			Value: &ast.Value{
+				Raw:  *reason,
+				Kind: ast.StringValue,
+			},
+		})
+	}
+
+	return ast.DirectiveList{
+		{
+			Name:      deprecatedDirectiveName,
+			Arguments: args,
+		},
+	}
 }
 
 func pointerString(s *string) string {
@@ -245,14 +408,17 @@ func pointerString(s *string) string {
 	return *s
 }
 
-func buildInputValue(input *InputValue) *ast.ArgumentDefinition {
-	typ := getType(&input.Type)
+func buildInputValue(input *InputValue) (*ast.ArgumentDefinition, error) {
+	typ, err := getType(&input.Type)
+	if err != nil {
+		return nil, err
+	}
 
 	var defaultValue *ast.Value
 	if input.DefaultValue != nil {
-		defaultValue = &ast.Value{
-			Raw:  pointerString(input.DefaultValue),
-			Kind: ast.Variable,
+		defaultValue, err = parseValue(*input.DefaultValue)
+		if err != nil {
+			return nil, xerrors.Errorf("invalid default value: %w", err)
 		}
 	}
 
@@ -261,29 +427,41 @@ func buildInputValue(input *InputValue) *ast.ArgumentDefinition {
 		Name:         input.Name,
 		DefaultValue: defaultValue,
 		Type:         typ,
-	}
+		Directives:   deprecatedDirectives(input.IsDeprecated, input.DeprecationReason),
+	}, nil
 }
 
-func getType(typeRef *TypeRef) *ast.Type {
+func getType(typeRef *TypeRef) (*ast.Type, error) {
 	if typeRef.Kind == TypeKindList {
 		itemRef := typeRef.OfType
 		if itemRef == nil {
-			panic("Decorated type deeper than introspection query.")
+			return nil, ErrTypeRefTruncated
+		}
+		itemType, err := getType(itemRef)
+		if err != nil {
+			return nil, err
 		}
 
-		return ast.ListType(getType(itemRef), nil)
+		return ast.ListType(itemType, nil), nil
 	}
 
 	if typeRef.Kind == TypeKindNonNull {
 		nullableRef := typeRef.OfType
 		if nullableRef == nil {
-			panic("Decorated type deeper than introspection query.")
+			return nil, ErrTypeRefTruncated
+		}
+		nullableType, err := getType(nullableRef)
+		if err != nil {
+			return nil, err
 		}
-		nullableType := getType(nullableRef)
 		nullableType.NonNull = true
 
-		return nullableType
+		return nullableType, nil
+	}
+
+	if typeRef.Name == nil {
+		return nil, xerrors.Errorf("%s type has no name", typeRef.Kind)
 	}
 
-	return ast.NamedType(pointerString(typeRef.Name), nil)
+	return ast.NamedType(*typeRef.Name, nil), nil
 }
@@ -0,0 +1,296 @@
+package introspection_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Yamashou/gqlgenc/introspection"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/validator"
+)
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func scalarRef(name string) introspection.TypeRef {
+	return introspection.TypeRef{Kind: introspection.TypeKindScalar, Name: stringPtr(name)}
+}
+
+func objectType(name string, fields ...*introspection.FieldValue) *introspection.FullType {
+	return &introspection.FullType{
+		Kind:   introspection.TypeKindObject,
+		Name:   stringPtr(name),
+		Fields: fields,
+	}
+}
+
+func field(name string, typ introspection.TypeRef) *introspection.FieldValue {
+	return &introspection.FieldValue{Name: name, Type: typ}
+}
+
+func newQuery(queryType, mutationType, subscriptionType *string, types ...*introspection.FullType) introspection.Query {
+	var query introspection.Query
+	query.Schema.QueryType.Name = queryType
+	if mutationType != nil {
+		query.Schema.MutationType = &struct {
+			Name *string `json:"name"`
+		}{Name: mutationType}
+	}
+	if subscriptionType != nil {
+		query.Schema.SubscriptionType = &struct {
+			Name *string `json:"name"`
+		}{Name: subscriptionType}
+	}
+	query.Schema.Types = append(introspection.FullTypes{
+		{Kind: introspection.TypeKindScalar, Name: stringPtr("String")},
+		{Kind: introspection.TypeKindScalar, Name: stringPtr("Boolean")},
+	}, types...)
+
+	return query
+}
+
+func parse(t *testing.T, query introspection.Query) *ast.Schema {
+	t.Helper()
+	doc, err := introspection.ParseIntrospectionQuery(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema, gqlerr := validator.ValidateSchemaDocument(doc)
+	if gqlerr != nil {
+		t.Fatal(gqlerr)
+	}
+
+	return schema
+}
+
+func rootName(def *ast.Definition) string {
+	if def == nil {
+		return ""
+	}
+
+	return def.Name
+}
+
+func TestParseIntrospectionQuery_roots(t *testing.T) {
+	tests := []struct {
+		name             string
+		query            introspection.Query
+		wantQuery        string
+		wantMutation     string
+		wantSubscription string
+	}{
+		{
+			name: "read only",
+			query: newQuery(stringPtr("Query"), nil, nil,
+				objectType("Query", field("name", scalarRef("String"))),
+			),
+			wantQuery: "Query",
+		},
+		{
+			name: "subscription only",
+			query: newQuery(nil, nil, stringPtr("Subscription"),
+				objectType("Subscription", field("name", scalarRef("String"))),
+			),
+			wantSubscription: "Subscription",
+		},
+		{
+			name: "custom root names",
+			query: newQuery(stringPtr("QueryRoot"), stringPtr("MutationRoot"), stringPtr("SubscriptionRoot"),
+				objectType("QueryRoot", field("name", scalarRef("String"))),
+				objectType("MutationRoot", field("name", scalarRef("String"))),
+				objectType("SubscriptionRoot", field("name", scalarRef("String"))),
+			),
+			wantQuery:        "QueryRoot",
+			wantMutation:     "MutationRoot",
+			wantSubscription: "SubscriptionRoot",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := parse(t, tt.query)
+			if got := rootName(schema.Query); got != tt.wantQuery {
+				t.Errorf("query root: want %q, got %q", tt.wantQuery, got)
+			}
+			if got := rootName(schema.Mutation); got != tt.wantMutation {
+				t.Errorf("mutation root: want %q, got %q", tt.wantMutation, got)
+			}
+			if got := rootName(schema.Subscription); got != tt.wantSubscription {
+				t.Errorf("subscription root: want %q, got %q", tt.wantSubscription, got)
+			}
+		})
+	}
+}
+
+func TestParseIntrospectionQuery_deprecated(t *testing.T) {
+	deprecated := field("old", scalarRef("String"))
+	deprecated.IsDeprecated = true
+	deprecated.DeprecationReason = stringPtr("use new")
+
+	schema := parse(t, newQuery(stringPtr("Query"), nil, nil,
+		objectType("Query", field("new", scalarRef("String")), deprecated),
+	))
+
+	directive := schema.Query.Fields.ForName("old").Directives.ForName("deprecated")
+	if directive == nil {
+		t.Fatal("@deprecated is not found")
+	}
+	if reason := directive.Arguments.ForName("reason"); reason == nil || reason.Value.Raw != "use new" {
+		t.Errorf("unexpected reason: %+v", reason)
+	}
+	if directives := schema.Query.Fields.ForName("new").Directives; len(directives) != 0 {
+		t.Errorf("unexpected directives: %+v", directives)
+	}
+}
+
+func TestParseIntrospectionQuery_capabilities(t *testing.T) {
+	trueValue := true
+	query := newQuery(stringPtr("Query"), nil, nil,
+		objectType("Query", field("now", scalarRef("DateTime"))),
+		&introspection.FullType{
+			Kind:           introspection.TypeKindScalar,
+			Name:           stringPtr("DateTime"),
+			SpecifiedByURL: stringPtr("https://scalars.graphql.org/andimarek/date-time"),
+		},
+		&introspection.FullType{
+			Kind:    introspection.TypeKindInputObject,
+			Name:    stringPtr("UserBy"),
+			IsOneOf: &trueValue,
+			InputFields: []*introspection.InputValue{
+				{Name: "id", Type: scalarRef("String")},
+				{Name: "email", Type: scalarRef("String"), IsDeprecated: true, DeprecationReason: stringPtr("use id")},
+			},
+		},
+	)
+	query.Schema.Description = stringPtr("the schema")
+	query.Schema.Directives = []*introspection.DirectiveType{
+		{Name: "tag", Locations: []string{"FIELD_DEFINITION"}, IsRepeatable: true},
+	}
+
+	doc, err := introspection.ParseIntrospectionQuery(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if description := doc.Schema[0].Description; description != "the schema" {
+		t.Errorf("unexpected schema description: %q", description)
+	}
+	if tag := doc.Directives.ForName("tag"); tag == nil || !tag.IsRepeatable {
+		t.Errorf("@tag is not repeatable: %+v", tag)
+	}
+
+	schema := parse(t, query)
+	specifiedBy := schema.Types["DateTime"].Directives.ForName("specifiedBy")
+	if specifiedBy == nil {
+		t.Fatal("@specifiedBy is not found")
+	}
+	if url := specifiedBy.Arguments.ForName("url"); url == nil || url.Value.Raw != "https://scalars.graphql.org/andimarek/date-time" {
+		t.Errorf("unexpected url: %+v", url)
+	}
+
+	userBy := schema.Types["UserBy"]
+	if userBy.Directives.ForName("oneOf") == nil {
+		t.Error("@oneOf is not found")
+	}
+	if userBy.Fields.ForName("email").Directives.ForName("deprecated") == nil {
+		t.Error("@deprecated is not found on the input field")
+	}
+	if directives := userBy.Fields.ForName("id").Directives; len(directives) != 0 {
+		t.Errorf("unexpected directives: %+v", directives)
+	}
+}
+
+func TestParseIntrospectionQuery_defaultValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		wantKind ast.ValueKind
+		want     string
+	}{
+		{name: "int", raw: "10", wantKind: ast.IntValue, want: "10"},
+		{name: "string", raw: `"a \"b\""`, wantKind: ast.StringValue, want: `"a \"b\""`},
+		{name: "enum", raw: "ASC", wantKind: ast.EnumValue, want: "ASC"},
+		{name: "null", raw: "null", wantKind: ast.NullValue, want: "null"},
+		{name: "list", raw: "[1, 2]", wantKind: ast.ListValue, want: "[1,2]"},
+		{name: "object", raw: `{field: "name", order: DESC}`, wantKind: ast.ObjectValue, want: `{field:"name",order:DESC}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := field("users", scalarRef("String"))
+			users.Args = []*introspection.InputValue{
+				{Name: "arg", Type: scalarRef("String"), DefaultValue: stringPtr(tt.raw)},
+			}
+			schema := parse(t, newQuery(stringPtr("Query"), nil, nil, objectType("Query", users)))
+
+			value := schema.Query.Fields.ForName("users").Arguments.ForName("arg").DefaultValue
+			if value.Kind != tt.wantKind {
+				t.Errorf("unexpected kind: %v", value.Kind)
+			}
+			if got := value.String(); got != tt.want {
+				t.Errorf("unexpected value: %s", got)
+			}
+		})
+	}
+}
+
+func TestParseIntrospectionQuery_errors(t *testing.T) {
+	truncated := field("matrix", introspection.TypeRef{Kind: introspection.TypeKindList})
+	invalidDefault := field("users", scalarRef("String"))
+	invalidDefault.Args = []*introspection.InputValue{
+		{Name: "first", Type: scalarRef("Int"), DefaultValue: stringPtr("{")},
+	}
+
+	tests := []struct {
+		name  string
+		query introspection.Query
+		want  string
+	}{
+		{
+			name:  "truncated type",
+			query: newQuery(stringPtr("Query"), nil, nil, objectType("Query", truncated)),
+			want:  "Query.matrix: " + introspection.ErrTypeRefTruncated.Error(),
+		},
+		{
+			name:  "invalid default value",
+			query: newQuery(stringPtr("Query"), nil, nil, objectType("Query", invalidDefault)),
+			want:  "Query.users(first:): invalid default value",
+		},
+		{
+			name: "unknown kind",
+			query: newQuery(stringPtr("Query"), nil, nil,
+				objectType("Query", field("name", scalarRef("String"))),
+				&introspection.FullType{Kind: "UNKNOWN", Name: stringPtr("Thing")},
+			),
+			want: `Thing: unknown kind "UNKNOWN"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := introspection.ParseIntrospectionQuery(tt.query)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("unexpected error: %v", err)
+			}
+			var schemaErr *introspection.SchemaError
+			if !errors.As(err, &schemaErr) {
+				t.Errorf("want SchemaError, got %T", err)
+			}
+		})
+	}
+}
+
+func TestQuery_TypeRefTruncated(t *testing.T) {
+	nonNullString := introspection.TypeRef{Kind: introspection.TypeKindNonNull, OfType: &introspection.TypeRef{Kind: introspection.TypeKindScalar, Name: stringPtr("String")}}
+	complete := newQuery(stringPtr("Query"), nil, nil, objectType("Query", field("name", nonNullString)))
+	if complete.TypeRefTruncated() {
+		t.Error("want not truncated")
+	}
+
+	truncated := newQuery(stringPtr("Query"), nil, nil, objectType("Query", field("name", introspection.TypeRef{Kind: introspection.TypeKindNonNull})))
+	if !truncated.TypeRefTruncated() {
+		t.Error("want truncated")
+	}
+}
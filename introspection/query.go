@@ -1,93 +1,247 @@
 package introspection
 
-const Introspection = `query Query {
-      __schema {
-        queryType { name }
-        mutationType { name }
-        subscriptionType { name }
-        types {
-          ...FullType
-        }
-        directives {
-          name
-          description
-          locations
-          args {
-            ...InputValue
-          }
-        }
-      }
-    }
+import (
+	"fmt"
+	"strings"
+)
+
+// Introspection is the introspection query which every server supports.
+var Introspection = BuildQuery(Capabilities{}, DefaultTypeRefDepth)
+
+// Capabilities are the introspection features added to the spec after its first release,
+// which servers may not support.
+type Capabilities struct {
+	// SchemaDescription is __Schema.description
+	SchemaDescription bool
+	// SpecifiedByURL is the name of __Type.specifiedByURL, which was once called specifiedByUrl.
+	// Empty if not supported.
+	SpecifiedByURL string
+	// DirectiveIsRepeatable is __Directive.isRepeatable
+	DirectiveIsRepeatable bool
+	// InputValueDeprecation is __InputValue.isDeprecated and includeDeprecated arguments of
+	// __Type.inputFields, __Field.args and __Directive.args
+	InputValueDeprecation bool
+	// OneOf is __Type.isOneOf
+	OneOf bool
+}
+
+// CapabilitiesQuery asks the server which fields its introspection types have.
+const CapabilitiesQuery = `query Capabilities {
+  schema: __type(name: "__Schema") { fields { name } }
+  type: __type(name: "__Type") { fields { name } }
+  directive: __type(name: "__Directive") { fields { name } }
+  inputValue: __type(name: "__InputValue") { fields { name } }
+}`
+
+type capabilityType struct {
+	Fields []struct {
+		Name string
+	}
+}
+
+func (t *capabilityType) hasField(name string) bool {
+	if t == nil {
+		return false
+	}
+	for _, field := range t.Fields {
+		if field.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CapabilitiesQueryResult is the result of CapabilitiesQuery.
+type CapabilitiesQueryResult struct {
+	Schema     *capabilityType `graphql:"schema"`
+	Type       *capabilityType `graphql:"type"`
+	Directive  *capabilityType `graphql:"directive"`
+	InputValue *capabilityType `graphql:"inputValue"`
+}
 
-    fragment FullType on __Type {
-      kind
+func (r *CapabilitiesQueryResult) Capabilities() Capabilities {
+	var c Capabilities
+	c.SchemaDescription = r.Schema.hasField("description")
+	switch {
+	case r.Type.hasField("specifiedByURL"):
+		c.SpecifiedByURL = "specifiedByURL"
+	case r.Type.hasField("specifiedByUrl"):
+		c.SpecifiedByURL = "specifiedByUrl"
+	}
+	c.DirectiveIsRepeatable = r.Directive.hasField("isRepeatable")
+	c.InputValueDeprecation = r.InputValue.hasField("isDeprecated")
+	c.OneOf = r.Type.hasField("isOneOf")
+
+	return c
+}
+
+// DefaultTypeRefDepth is how many levels of a type TypeRef asks for by default, enough for types like [[String!]!]!.
+const DefaultTypeRefDepth = 8
+
+// BuildQuery builds the introspection query which asks for every field the capabilities allow,
+// and for typeRefDepth levels of each type.
+func BuildQuery(c Capabilities, typeRefDepth int) string {
+	var schemaFields, typeFields, directiveFields, inputValueFields, includeDeprecatedInputValues []string
+	if c.SchemaDescription {
+		schemaFields = append(schemaFields, "description")
+	}
+	if c.SpecifiedByURL != "" {
+		typeFields = append(typeFields, "specifiedByURL: "+c.SpecifiedByURL)
+	}
+	if c.OneOf {
+		typeFields = append(typeFields, "isOneOf")
+	}
+	if c.DirectiveIsRepeatable {
+		directiveFields = append(directiveFields, "isRepeatable")
+	}
+	if c.InputValueDeprecation {
+		inputValueFields = append(inputValueFields, "isDeprecated", "deprecationReason")
+		includeDeprecatedInputValues = append(includeDeprecatedInputValues, "(includeDeprecated: true)")
+	}
+	includeDeprecated := strings.Join(includeDeprecatedInputValues, "")
+
+	return fmt.Sprintf(`query Query {
+  __schema {
+    %[1]squeryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types {
+      ...FullType
+    }
+    directives {
       name
       description
-      fields(includeDeprecated: true) {
-        name
-        description
-        args {
-          ...InputValue
-        }
-        type {
-          ...TypeRef
-        }
-        isDeprecated
-        deprecationReason
-      }
-      inputFields {
+      %[2]slocations
+      args%[5]s {
         ...InputValue
       }
-      interfaces {
-        ...TypeRef
-      }
-      enumValues(includeDeprecated: true) {
-        name
-        description
-        isDeprecated
-        deprecationReason
-      }
-      possibleTypes {
-        ...TypeRef
-      }
     }
+  }
+}
 
-    fragment InputValue on __InputValue {
-      name
-      description
-      type { ...TypeRef }
-      defaultValue
+fragment FullType on __Type {
+  kind
+  name
+  description
+  %[3]sfields(includeDeprecated: true) {
+    name
+    description
+    args%[5]s {
+      ...InputValue
+    }
+    type {
+      ...TypeRef
     }
+    isDeprecated
+    deprecationReason
+  }
+  inputFields%[5]s {
+    ...InputValue
+  }
+  interfaces {
+    ...TypeRef
+  }
+  enumValues(includeDeprecated: true) {
+    name
+    description
+    isDeprecated
+    deprecationReason
+  }
+  possibleTypes {
+    ...TypeRef
+  }
+}
 
-    fragment TypeRef on __Type {
-      kind
-      name
-      ofType {
-        kind
-        name
-        ofType {
-          kind
-          name
-          ofType {
-            kind
-            name
-            ofType {
-              kind
-              name
-              ofType {
-                kind
-                name
-                ofType {
-                  kind
-                  name
-                  ofType {
-                    kind
-                    name
-                  }
-                }
-              }
-            }
-          }
-        }
-      }
-    }`
+fragment InputValue on __InputValue {
+  name
+  description
+  type { ...TypeRef }
+  %[4]sdefaultValue
+}
+
+fragment TypeRef on __Type {
+%[6]s
+}`,
+		fieldLines(schemaFields, 4),
+		fieldLines(directiveFields, 6),
+		fieldLines(typeFields, 2),
+		fieldLines(inputValueFields, 2),
+		includeDeprecated,
+		typeRef(typeRefDepth),
+	)
+}
+
+// fieldLines returns the fields each followed by a new line and the indent for the next line.
+func fieldLines(fields []string, indent int) string {
+	var b strings.Builder
+	for _, field := range fields {
+		b.WriteString(field)
+		b.WriteString("\n")
+		b.WriteString(strings.Repeat(" ", indent))
+	}
+
+	return b.String()
+}
+
+func typeRef(depth int) string {
+	var b strings.Builder
+	for i := 0; i < depth; i++ {
+		indent := strings.Repeat("  ", i+1)
+		if i > 0 {
+			b.WriteString(" {\n")
+		}
+		fmt.Fprintf(&b, "%skind\n%sname", indent, indent)
+		if i < depth-1 {
+			fmt.Fprintf(&b, "\n%sofType", indent)
+		}
+	}
+	for i := depth - 1; i > 0; i-- {
+		fmt.Fprintf(&b, "\n%s}", strings.Repeat("  ", i))
+	}
+
+	return b.String()
+}
+
+// TypeRefTruncated reports whether a type is wrapped in more lists and non-nulls than the query asked for,
+// so that the query must be sent again with a larger typeRefDepth.
+func (q *Query) TypeRefTruncated() bool {
+	var truncated func(typeRef *TypeRef) bool
+	truncated = func(typeRef *TypeRef) bool {
+		if typeRef.Kind != TypeKindList && typeRef.Kind != TypeKindNonNull {
+			return false
+		}
+		if typeRef.OfType == nil {
+			return true
+		}
+
+		return truncated(typeRef.OfType)
+	}
+	inputValuesTruncated := func(inputValues []*InputValue) bool {
+		for _, inputValue := range inputValues {
+			if truncated(&inputValue.Type) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for _, typ := range q.Schema.Types {
+		for _, field := range typ.Fields {
+			if truncated(&field.Type) || inputValuesTruncated(field.Args) {
+				return true
+			}
+		}
+		if inputValuesTruncated(typ.InputFields) {
+			return true
+		}
+	}
+	for _, directive := range q.Schema.Directives {
+		if inputValuesTruncated(directive.Args) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,82 @@
+package introspection_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Yamashou/gqlgenc/graphqljson"
+	"github.com/Yamashou/gqlgenc/introspection"
+	"github.com/google/go-cmp/cmp"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+func TestBuildQuery(t *testing.T) {
+	tests := []struct {
+		name         string
+		capabilities introspection.Capabilities
+		want         []string
+		notWant      []string
+	}{
+		{
+			name:    "none",
+			notWant: []string{"specifiedBy", "isOneOf", "isRepeatable", "isDeprecated\n  deprecationReason\n  defaultValue", "args(includeDeprecated: true)"},
+		},
+		{
+			name: "all",
+			capabilities: introspection.Capabilities{
+				SchemaDescription:     true,
+				SpecifiedByURL:        "specifiedByURL",
+				DirectiveIsRepeatable: true,
+				InputValueDeprecation: true,
+				OneOf:                 true,
+			},
+			want: []string{"specifiedByURL: specifiedByURL", "isOneOf", "isRepeatable", "args(includeDeprecated: true)", "inputFields(includeDeprecated: true)"},
+		},
+		{
+			name:         "legacy specifiedByUrl",
+			capabilities: introspection.Capabilities{SpecifiedByURL: "specifiedByUrl"},
+			want:         []string{"specifiedByURL: specifiedByUrl"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := introspection.BuildQuery(tt.capabilities, introspection.DefaultTypeRefDepth)
+			for _, want := range tt.want {
+				if !strings.Contains(query, want) {
+					t.Errorf("query does not contain %q", want)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(query, notWant) {
+					t.Errorf("query contains %q", notWant)
+				}
+			}
+			if _, err := parser.ParseQuery(&ast.Source{Input: query}); err != nil {
+				t.Errorf("invalid query: %v", err)
+			}
+		})
+	}
+}
+
+func TestCapabilitiesQueryResult_Capabilities(t *testing.T) {
+	data := `{
+		"schema": {"fields": [{"name": "description"}, {"name": "types"}]},
+		"type": {"fields": [{"name": "name"}, {"name": "specifiedByUrl"}]},
+		"directive": {"fields": [{"name": "name"}]},
+		"inputValue": {"fields": [{"name": "name"}, {"name": "isDeprecated"}]}
+	}`
+	var res introspection.CapabilitiesQueryResult
+	if err := graphqljson.UnmarshalData([]byte(data), &res); err != nil {
+		t.Fatal(err)
+	}
+
+	want := introspection.Capabilities{
+		SchemaDescription:     true,
+		SpecifiedByURL:        "specifiedByUrl",
+		InputValueDeprecation: true,
+	}
+	if diff := cmp.Diff(want, res.Capabilities()); diff != "" {
+		t.Errorf("capabilities mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -25,56 +25,70 @@ func (fs FullTypes) NameMap() map[string]*FullType {
 }
 
 type FullType struct {
-	Kind        TypeKind
-	Name        *string
-	Description *string
-	Fields      []*FieldValue
-	InputFields []*InputValue
-	Interfaces  []*TypeRef
-	EnumValues  []*struct {
-		Name              string
-		Description       *string
-		IsDeprecated      bool
-		DeprecationReason *string
-	}
-	PossibleTypes []*TypeRef
+	Kind        TypeKind `json:"kind"`
+	Name        *string  `json:"name"`
+	Description *string  `json:"description"`
+	// SpecifiedByURL and IsOneOf are only set if the server supports them, see Capabilities.
+	SpecifiedByURL *string       `json:"specifiedByURL"`
+	IsOneOf        *bool         `json:"isOneOf"`
+	Fields         []*FieldValue `json:"fields"`
+	InputFields    []*InputValue `json:"inputFields"`
+	Interfaces     []*TypeRef    `json:"interfaces"`
+	EnumValues     []*struct {
+		Name              string  `json:"name"`
+		Description       *string `json:"description"`
+		IsDeprecated      bool    `json:"isDeprecated"`
+		DeprecationReason *string `json:"deprecationReason"`
+	} `json:"enumValues"`
+	PossibleTypes []*TypeRef `json:"possibleTypes"`
 }
 
 type FieldValue struct {
-	Name              string
-	Description       *string
-	Args              []*InputValue
-	Type              TypeRef
-	IsDeprecated      bool
-	DeprecationReason *string
+	Name              string        `json:"name"`
+	Description       *string       `json:"description"`
+	Args              []*InputValue `json:"args"`
+	Type              TypeRef       `json:"type"`
+	IsDeprecated      bool          `json:"isDeprecated"`
+	DeprecationReason *string       `json:"deprecationReason"`
 }
 
 type InputValue struct {
-	Name         string
-	Description  *string
-	Type         TypeRef
-	DefaultValue *string
+	Name         string  `json:"name"`
+	Description  *string `json:"description"`
+	Type         TypeRef `json:"type"`
+	DefaultValue *string `json:"defaultValue"`
+	// IsDeprecated and DeprecationReason are only set if the server supports input value deprecation.
+	IsDeprecated      bool    `json:"isDeprecated"`
+	DeprecationReason *string `json:"deprecationReason"`
 }
 
 type TypeRef struct {
-	Kind   TypeKind
-	Name   *string
-	OfType *TypeRef
+	Kind   TypeKind `json:"kind"`
+	Name   *string  `json:"name"`
+	OfType *TypeRef `json:"ofType"`
 }
 
 type Query struct {
 	Schema struct {
-		QueryType        struct{ Name *string }
-		MutationType     *struct{ Name *string }
-		SubscriptionType *struct{ Name *string }
-		Types            FullTypes
-		Directives       []*DirectiveType
-	} `graphql:"__schema"`
+		Description *string `json:"description"`
+		QueryType   struct {
+			Name *string `json:"name"`
+		} `json:"queryType"`
+		MutationType *struct {
+			Name *string `json:"name"`
+		} `json:"mutationType"`
+		SubscriptionType *struct {
+			Name *string `json:"name"`
+		} `json:"subscriptionType"`
+		Types      FullTypes        `json:"types"`
+		Directives []*DirectiveType `json:"directives"`
+	} `graphql:"__schema" json:"__schema"`
 }
 
 type DirectiveType struct {
-	Name        string
-	Description *string
-	Locations   []string
-	Args        []*InputValue
+	Name         string        `json:"name"`
+	Description  *string       `json:"description"`
+	IsRepeatable bool          `json:"isRepeatable"`
+	Locations    []string      `json:"locations"`
+	Args         []*InputValue `json:"args"`
 }
@@ -0,0 +1,54 @@
+package introspection
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+	"golang.org/x/xerrors"
+)
+
+// parseValue parses a GraphQL value literal such as a defaultValue of the introspection result.
+// gqlparser doesn't expose its value parser, so the literal is parsed as the argument of a query.
+func parseValue(raw string) (*ast.Value, error) {
+	doc, err := parser.ParseQuery(&ast.Source{Input: "{_(_:" + raw + ")}"})
+	if err != nil {
+		return nil, xerrors.Errorf("invalid value %s: %w", raw, err)
+	}
+
+	if len(doc.Operations) != 1 || len(doc.Operations[0].SelectionSet) != 1 {
+		return nil, xerrors.Errorf("invalid value %s", raw)
+	}
+	field, ok := doc.Operations[0].SelectionSet[0].(*ast.Field)
+	if !ok || len(field.Arguments) != 1 || len(field.SelectionSet) != 0 {
+		return nil, xerrors.Errorf("invalid value %s", raw)
+	}
+
+	value := field.Arguments[0].Value
+	if !isConst(value) {
+		return nil, xerrors.Errorf("invalid value %s: variables are not allowed", raw)
+	}
+	clearPosition(value)
+
+	return value, nil
+}
+
+func isConst(value *ast.Value) bool {
+	if value.Kind == ast.Variable {
+		return false
+	}
+	for _, child := range value.Children {
+		if !isConst(child.Value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// clearPosition removes the positions, which point into the query built by parseValue.
+func clearPosition(value *ast.Value) {
+	value.Position = nil
+	for _, child := range value.Children {
+		child.Position = nil
+		clearPosition(child.Value)
+	}
+}
@@ -2,26 +2,67 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"os"
 
 	"github.com/99designs/gqlgen/api"
-	"github.com/Yamashou/gqlgenc/clientgen"
 	"github.com/Yamashou/gqlgenc/config"
 	"github.com/Yamashou/gqlgenc/generator"
 )
 
+const configFilename = ".gqlgenc.yml"
+
 func main() {
 	ctx := context.Background()
-	cfg, err := config.LoadConfig(".gqlgenc.yml")
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "schema":
+			os.Exit(runSchema(ctx, os.Args[2:]))
+		case "diff":
+			os.Exit(runDiff(ctx, os.Args[2:]))
+		}
+	}
+
+	checkOnly := flag.Bool("check", false, "check that the generated files are up to date, without writing anything")
+	diagnostics := flag.String("diagnostics", "text", "output format of errors in query files, text or json")
+	watch := flag.Bool("watch", false, "generate again whenever the query files, the config file or the local schema files change")
+	flag.Parse()
+
+	if *diagnostics != "text" && *diagnostics != "json" {
+		fmt.Fprintf(os.Stderr, "unknown diagnostics format %q\n", *diagnostics)
+		os.Exit(2)
+	}
+
+	if *watch {
+		os.Exit(runWatch(ctx, *diagnostics))
+	}
+
+	cfg, err := config.LoadConfig(configFilename)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "%+v", err.Error())
 		os.Exit(2)
 	}
+	cfg.Warn = printWarning
+
+	clientPlugin := generator.NewClientPlugin(cfg)
+	if *checkOnly {
+		// the diff of the schema lock is printed even if the changed schema breaks generation
+		diff, err := generator.Check(ctx, cfg, api.AddPlugin(clientPlugin))
+		fmt.Fprint(os.Stderr, diff)
+		if err != nil {
+			printGenerateError(err, *diagnostics)
+			os.Exit(4)
+		}
+		if diff != "" {
+			os.Exit(1)
+		}
+
+		return
+	}
 
-	clientPlugin := clientgen.New(cfg.Query, cfg.Client)
 	if err := generator.Generate(ctx, cfg, api.AddPlugin(clientPlugin)); err != nil {
-		fmt.Fprintf(os.Stderr, "%+v", err.Error())
+		printGenerateError(err, *diagnostics)
 		os.Exit(4)
 	}
 }
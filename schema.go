@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Yamashou/gqlgenc/config"
+	"github.com/Yamashou/gqlgenc/introspection"
+	"github.com/Yamashou/gqlgenc/sdl"
+	"github.com/vektah/gqlparser/v2/validator"
+)
+
+const schemaUsage = `usage: gqlgenc schema <command> [flags]
+
+commands:
+  dump    print the schema introspected from the endpoint
+`
+
+func runSchema(ctx context.Context, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, schemaUsage)
+
+		return 2
+	}
+
+	switch args[0] {
+	case "dump":
+		return dumpSchema(ctx, args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n%s", args[0], schemaUsage)
+
+		return 2
+	}
+}
+
+func dumpSchema(ctx context.Context, args []string) int {
+	flags := flag.NewFlagSet("schema dump", flag.ExitOnError)
+	format := flags.String("format", "sdl", "output format, sdl or json")
+	_ = flags.Parse(args)
+
+	if *format != "sdl" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "unknown format %q\n", *format)
+
+		return 2
+	}
+
+	cfg, err := config.LoadConfig(configFilename)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%+v", err.Error())
+
+		return 2
+	}
+	cfg.Warn = printWarning
+
+	res, err := cfg.IntrospectEndpoint(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%+v", err.Error())
+
+		return 4
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(map[string]interface{}{"data": res}); err != nil {
+			fmt.Fprintf(os.Stderr, "%+v", err.Error())
+
+			return 4
+		}
+
+		return 0
+	}
+
+	doc, err := introspection.ParseIntrospectionQuery(*res)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%+v", err)
+
+		return 4
+	}
+
+	schema, gqlerr := validator.ValidateSchemaDocument(doc)
+	if gqlerr != nil {
+		fmt.Fprintf(os.Stderr, "%+v", gqlerr.Error())
+
+		return 4
+	}
+
+	fmt.Print(sdl.Format(schema))
+
+	return 0
+}
@@ -0,0 +1,420 @@
+package schemadiff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+type Criticality int
+
+const (
+	// Breaking changes break operations which use the changed part of the schema.
+	Breaking Criticality = iota
+	// Dangerous changes don't break operations but may change their results at runtime,
+	// e.g. a new enum value which clients don't know.
+	Dangerous
+	// Safe changes don't affect existing operations.
+	Safe
+)
+
+func (c Criticality) String() string {
+	switch c {
+	case Breaking:
+		return "BREAKING"
+	case Dangerous:
+		return "DANGEROUS"
+	case Safe:
+		return "SAFE"
+	}
+
+	return fmt.Sprintf("Criticality(%d)", int(c))
+}
+
+// Change is a difference between two schemas.
+type Change struct {
+	Criticality Criticality
+	// Coordinate is the part of the old schema the change is about, such as User, User.name or User.name(id:).
+	Coordinate string
+	Message    string
+}
+
+// Compare returns the changes from oldSchema to newSchema, sorted by criticality and coordinate.
+func Compare(oldSchema, newSchema *ast.Schema) []*Change {
+	var d differ
+	d.compareRoots(oldSchema, newSchema)
+
+	for _, name := range sortedTypeNames(oldSchema.Types) {
+		if isSpecifiedType(name) {
+			continue
+		}
+		oldDef := oldSchema.Types[name]
+		newDef, ok := newSchema.Types[name]
+		if !ok {
+			d.add(Breaking, name, "type %s was removed", name)
+
+			continue
+		}
+		d.compareDefinition(oldDef, newDef)
+	}
+
+	for _, name := range sortedTypeNames(newSchema.Types) {
+		if _, ok := oldSchema.Types[name]; !ok && !isSpecifiedType(name) {
+			d.add(Safe, name, "type %s was added", name)
+		}
+	}
+
+	d.compareDirectives(oldSchema.Directives, newSchema.Directives)
+
+	sort.SliceStable(d.changes, func(i, j int) bool {
+		if d.changes[i].Criticality != d.changes[j].Criticality {
+			return d.changes[i].Criticality < d.changes[j].Criticality
+		}
+
+		return d.changes[i].Coordinate < d.changes[j].Coordinate
+	})
+
+	return d.changes
+}
+
+type differ struct {
+	changes []*Change
+}
+
+func (d *differ) add(criticality Criticality, coordinate, format string, args ...interface{}) {
+	d.changes = append(d.changes, &Change{
+		Criticality: criticality,
+		Coordinate:  coordinate,
+		Message:     fmt.Sprintf(format, args...),
+	})
+}
+
+func (d *differ) compareRoots(oldSchema, newSchema *ast.Schema) {
+	roots := []struct {
+		operation ast.Operation
+		old, new  *ast.Definition
+	}{
+		{ast.Query, oldSchema.Query, newSchema.Query},
+		{ast.Mutation, oldSchema.Mutation, newSchema.Mutation},
+		{ast.Subscription, oldSchema.Subscription, newSchema.Subscription},
+	}
+
+	for _, root := range roots {
+		switch {
+		case root.old == nil && root.new == nil:
+		case root.old == nil:
+			d.add(Safe, root.new.Name, "%s root type %s was added", root.operation, root.new.Name)
+		case root.new == nil:
+			d.add(Breaking, root.old.Name, "%s root type %s was removed", root.operation, root.old.Name)
+		case root.old.Name != root.new.Name:
+			d.add(Breaking, root.old.Name, "%s root type changed from %s to %s", root.operation, root.old.Name, root.new.Name)
+		}
+	}
+}
+
+func (d *differ) compareDefinition(oldDef, newDef *ast.Definition) {
+	if oldDef.Kind != newDef.Kind {
+		d.add(Breaking, oldDef.Name, "type %s changed from %s to %s", oldDef.Name, oldDef.Kind, newDef.Kind)
+
+		return
+	}
+
+	switch oldDef.Kind {
+	case ast.Object, ast.Interface:
+		d.compareInterfaces(oldDef, newDef)
+		d.compareFields(oldDef, newDef)
+	case ast.InputObject:
+		d.compareInputFields(oldDef, newDef)
+	case ast.Union:
+		d.compareUnionMembers(oldDef, newDef)
+	case ast.Enum:
+		d.compareEnumValues(oldDef, newDef)
+	case ast.Scalar:
+	}
+}
+
+func (d *differ) compareInterfaces(oldDef, newDef *ast.Definition) {
+	for _, name := range oldDef.Interfaces {
+		if !containsString(newDef.Interfaces, name) {
+			d.add(Breaking, oldDef.Name, "%s no longer implements interface %s", oldDef.Name, name)
+		}
+	}
+	for _, name := range newDef.Interfaces {
+		if !containsString(oldDef.Interfaces, name) {
+			d.add(Dangerous, oldDef.Name, "%s now implements interface %s", oldDef.Name, name)
+		}
+	}
+}
+
+func (d *differ) compareFields(oldDef, newDef *ast.Definition) {
+	for _, oldField := range oldDef.Fields {
+		if isIntrospectionField(oldField.Name) {
+			continue
+		}
+		coordinate := oldDef.Name + "." + oldField.Name
+		newField := newDef.Fields.ForName(oldField.Name)
+		if newField == nil {
+			d.add(Breaking, coordinate, "field %s was removed", coordinate)
+
+			continue
+		}
+
+		if !isSafeOutputTypeChange(oldField.Type, newField.Type) {
+			d.add(Breaking, coordinate, "field %s changed type from %s to %s", coordinate, oldField.Type, newField.Type)
+		} else if oldField.Type.String() != newField.Type.String() {
+			d.add(Safe, coordinate, "field %s changed type from %s to %s", coordinate, oldField.Type, newField.Type)
+		}
+
+		d.compareArguments(coordinate, oldField.Arguments, newField.Arguments)
+		d.compareDeprecation(coordinate, oldField.Directives, newField.Directives)
+	}
+
+	for _, newField := range newDef.Fields {
+		if oldDef.Fields.ForName(newField.Name) == nil {
+			coordinate := oldDef.Name + "." + newField.Name
+			d.add(Safe, coordinate, "field %s was added", coordinate)
+		}
+	}
+}
+
+func (d *differ) compareArguments(fieldCoordinate string, oldArgs, newArgs ast.ArgumentDefinitionList) {
+	for _, oldArg := range oldArgs {
+		coordinate := fmt.Sprintf("%s(%s:)", fieldCoordinate, oldArg.Name)
+		newArg := newArgs.ForName(oldArg.Name)
+		if newArg == nil {
+			d.add(Breaking, coordinate, "argument %s was removed", coordinate)
+
+			continue
+		}
+
+		d.compareInputValue(coordinate, "argument", oldArg.Type, newArg.Type, oldArg.DefaultValue, newArg.DefaultValue)
+	}
+
+	for _, newArg := range newArgs {
+		if oldArgs.ForName(newArg.Name) != nil {
+			continue
+		}
+		coordinate := fmt.Sprintf("%s(%s:)", fieldCoordinate, newArg.Name)
+		if isRequired(newArg.Type, newArg.DefaultValue) {
+			// every operation selecting the field breaks, not only those which use the argument
+			d.add(Breaking, fieldCoordinate, "required argument %s was added", coordinate)
+		} else {
+			d.add(Dangerous, coordinate, "optional argument %s was added", coordinate)
+		}
+	}
+}
+
+func (d *differ) compareInputFields(oldDef, newDef *ast.Definition) {
+	for _, oldField := range oldDef.Fields {
+		coordinate := oldDef.Name + "." + oldField.Name
+		newField := newDef.Fields.ForName(oldField.Name)
+		if newField == nil {
+			d.add(Breaking, coordinate, "input field %s was removed", coordinate)
+
+			continue
+		}
+
+		d.compareInputValue(coordinate, "input field", oldField.Type, newField.Type, oldField.DefaultValue, newField.DefaultValue)
+	}
+
+	for _, newField := range newDef.Fields {
+		if oldDef.Fields.ForName(newField.Name) != nil {
+			continue
+		}
+		coordinate := oldDef.Name + "." + newField.Name
+		if isRequired(newField.Type, newField.DefaultValue) {
+			// every operation using the input object breaks
+			d.add(Breaking, oldDef.Name, "required input field %s was added", coordinate)
+		} else {
+			d.add(Dangerous, coordinate, "optional input field %s was added", coordinate)
+		}
+	}
+}
+
+func (d *differ) compareInputValue(coordinate, kind string, oldType, newType *ast.Type, oldDefault, newDefault *ast.Value) {
+	if !isSafeInputTypeChange(oldType, newType) {
+		d.add(Breaking, coordinate, "%s %s changed type from %s to %s", kind, coordinate, oldType, newType)
+	} else if oldType.String() != newType.String() {
+		d.add(Safe, coordinate, "%s %s changed type from %s to %s", kind, coordinate, oldType, newType)
+	}
+
+	if valueString(oldDefault) != valueString(newDefault) {
+		d.add(Dangerous, coordinate, "default value of %s %s changed from %s to %s", kind, coordinate, valueString(oldDefault), valueString(newDefault))
+	}
+}
+
+func (d *differ) compareUnionMembers(oldDef, newDef *ast.Definition) {
+	for _, name := range oldDef.Types {
+		if !containsString(newDef.Types, name) {
+			d.add(Breaking, oldDef.Name, "type %s was removed from union %s", name, oldDef.Name)
+		}
+	}
+	for _, name := range newDef.Types {
+		if !containsString(oldDef.Types, name) {
+			d.add(Dangerous, oldDef.Name, "type %s was added to union %s", name, oldDef.Name)
+		}
+	}
+}
+
+func (d *differ) compareEnumValues(oldDef, newDef *ast.Definition) {
+	for _, oldValue := range oldDef.EnumValues {
+		coordinate := oldDef.Name + "." + oldValue.Name
+		newValue := newDef.EnumValues.ForName(oldValue.Name)
+		if newValue == nil {
+			// operations may receive or send any value of the enum
+			d.add(Breaking, oldDef.Name, "enum value %s was removed", coordinate)
+
+			continue
+		}
+		d.compareDeprecation(coordinate, oldValue.Directives, newValue.Directives)
+	}
+	for _, newValue := range newDef.EnumValues {
+		if oldDef.EnumValues.ForName(newValue.Name) == nil {
+			d.add(Dangerous, oldDef.Name, "enum value %s.%s was added", oldDef.Name, newValue.Name)
+		}
+	}
+}
+
+func (d *differ) compareDeprecation(coordinate string, oldDirectives, newDirectives ast.DirectiveList) {
+	oldDeprecated := oldDirectives.ForName("deprecated") != nil
+	newDeprecated := newDirectives.ForName("deprecated") != nil
+	switch {
+	case !oldDeprecated && newDeprecated:
+		d.add(Safe, coordinate, "%s was deprecated", coordinate)
+	case oldDeprecated && !newDeprecated:
+		d.add(Safe, coordinate, "%s is no longer deprecated", coordinate)
+	}
+}
+
+func (d *differ) compareDirectives(oldDirectives, newDirectives map[string]*ast.DirectiveDefinition) {
+	for _, name := range sortedDirectiveNames(oldDirectives) {
+		if isSpecifiedDirective(name) {
+			continue
+		}
+		coordinate := "@" + name
+		newDirective, ok := newDirectives[name]
+		if !ok {
+			d.add(Breaking, coordinate, "directive %s was removed", coordinate)
+
+			continue
+		}
+
+		d.compareArguments(coordinate, oldDirectives[name].Arguments, newDirective.Arguments)
+
+		for _, location := range oldDirectives[name].Locations {
+			if !containsLocation(newDirective.Locations, location) {
+				d.add(Breaking, coordinate, "location %s was removed from directive %s", location, coordinate)
+			}
+		}
+	}
+
+	for _, name := range sortedDirectiveNames(newDirectives) {
+		if _, ok := oldDirectives[name]; !ok && !isSpecifiedDirective(name) {
+			d.add(Safe, "@"+name, "directive @%s was added", name)
+		}
+	}
+}
+
+// isSafeOutputTypeChange reports whether results of newType can be read as oldType.
+func isSafeOutputTypeChange(oldType, newType *ast.Type) bool {
+	if oldType.NonNull && !newType.NonNull {
+		return false
+	}
+	if oldType.Elem != nil {
+		return newType.Elem != nil && isSafeOutputTypeChange(oldType.Elem, newType.Elem)
+	}
+
+	return newType.Elem == nil && oldType.NamedType == newType.NamedType
+}
+
+// isSafeInputTypeChange reports whether values of oldType are still accepted as newType.
+func isSafeInputTypeChange(oldType, newType *ast.Type) bool {
+	if !oldType.NonNull && newType.NonNull {
+		return false
+	}
+	if oldType.Elem != nil {
+		return newType.Elem != nil && isSafeInputTypeChange(oldType.Elem, newType.Elem)
+	}
+
+	return newType.Elem == nil && oldType.NamedType == newType.NamedType
+}
+
+func isRequired(typ *ast.Type, defaultValue *ast.Value) bool {
+	return typ.NonNull && defaultValue == nil
+}
+
+func valueString(value *ast.Value) string {
+	if value == nil {
+		return "none"
+	}
+
+	return value.String()
+}
+
+// isSpecifiedType reports whether the type is defined by the spec, which every schema has implicitly
+// even if an introspection result omits it.
+func isSpecifiedType(name string) bool {
+	switch name {
+	case "Int", "Float", "String", "Boolean", "ID":
+		return true
+	}
+
+	return strings.HasPrefix(name, "__")
+}
+
+// isSpecifiedDirective reports whether the directive is defined by the spec or by the prelude of gqlparser,
+// which an introspection result may leave out or list differently from a schema loaded from SDL.
+func isSpecifiedDirective(name string) bool {
+	switch name {
+	case "include", "skip", "deprecated", "specifiedBy", "oneOf", "defer":
+		return true
+	}
+
+	return false
+}
+
+func isIntrospectionField(name string) bool {
+	return name == "__schema" || name == "__type" || name == "__typename"
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsLocation(list []ast.DirectiveLocation, location ast.DirectiveLocation) bool {
+	for _, v := range list {
+		if v == location {
+			return true
+		}
+	}
+
+	return false
+}
+
+func sortedTypeNames(types map[string]*ast.Definition) []string {
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func sortedDirectiveNames(directives map[string]*ast.DirectiveDefinition) []string {
+	names := make([]string, 0, len(directives))
+	for name := range directives {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
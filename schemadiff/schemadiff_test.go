@@ -0,0 +1,198 @@
+package schemadiff_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Yamashou/gqlgenc/introspection"
+	"github.com/Yamashou/gqlgenc/schemadiff"
+	"github.com/google/go-cmp/cmp"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/validator"
+)
+
+func loadSchema(t *testing.T, input string) *ast.Schema {
+	t.Helper()
+	schema, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: input})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return schema
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name      string
+		oldSchema string
+		newSchema string
+		want      []*schemadiff.Change
+	}{
+		{
+			name:      "field removed",
+			oldSchema: `type Query { a: String b: String }`,
+			newSchema: `type Query { a: String }`,
+			want: []*schemadiff.Change{
+				{Criticality: schemadiff.Breaking, Coordinate: "Query.b", Message: "field Query.b was removed"},
+			},
+		},
+		{
+			name:      "output nullability",
+			oldSchema: `type Query { a: String b: String! }`,
+			newSchema: `type Query { a: String! b: String }`,
+			want: []*schemadiff.Change{
+				{Criticality: schemadiff.Breaking, Coordinate: "Query.b", Message: "field Query.b changed type from String! to String"},
+				{Criticality: schemadiff.Safe, Coordinate: "Query.a", Message: "field Query.a changed type from String to String!"},
+			},
+		},
+		{
+			name:      "arguments",
+			oldSchema: `type Query { a(x: Int, y: Int!): String }`,
+			newSchema: `type Query { a(x: Int!, y: Int, z: Int!, w: Int): String }`,
+			want: []*schemadiff.Change{
+				{Criticality: schemadiff.Breaking, Coordinate: "Query.a", Message: "required argument Query.a(z:) was added"},
+				{Criticality: schemadiff.Breaking, Coordinate: "Query.a(x:)", Message: "argument Query.a(x:) changed type from Int to Int!"},
+				{Criticality: schemadiff.Dangerous, Coordinate: "Query.a(w:)", Message: "optional argument Query.a(w:) was added"},
+				{Criticality: schemadiff.Safe, Coordinate: "Query.a(y:)", Message: "argument Query.a(y:) changed type from Int! to Int"},
+			},
+		},
+		{
+			name:      "enum values",
+			oldSchema: `type Query { a: Color } enum Color { RED GREEN }`,
+			newSchema: `type Query { a: Color } enum Color { RED BLUE }`,
+			want: []*schemadiff.Change{
+				{Criticality: schemadiff.Breaking, Coordinate: "Color", Message: "enum value Color.GREEN was removed"},
+				{Criticality: schemadiff.Dangerous, Coordinate: "Color", Message: "enum value Color.BLUE was added"},
+			},
+		},
+		{
+			name:      "input fields",
+			oldSchema: `type Query { a(in: In): String } input In { x: Int }`,
+			newSchema: `type Query { a(in: In): String } input In { x: Int = 1 y: Int! }`,
+			want: []*schemadiff.Change{
+				{Criticality: schemadiff.Breaking, Coordinate: "In", Message: "required input field In.y was added"},
+				{Criticality: schemadiff.Dangerous, Coordinate: "In.x", Message: "default value of input field In.x changed from none to 1"},
+			},
+		},
+		{
+			name:      "types",
+			oldSchema: `type Query { a: String } type A { a: String } union U = A`,
+			newSchema: `type Query { a: String } interface A { a: String } type B { b: String }`,
+			want: []*schemadiff.Change{
+				{Criticality: schemadiff.Breaking, Coordinate: "A", Message: "type A changed from OBJECT to INTERFACE"},
+				{Criticality: schemadiff.Breaking, Coordinate: "U", Message: "type U was removed"},
+				{Criticality: schemadiff.Safe, Coordinate: "B", Message: "type B was added"},
+			},
+		},
+		{
+			name:      "deprecation",
+			oldSchema: `type Query { a: String }`,
+			newSchema: `type Query { a: String @deprecated }`,
+			want: []*schemadiff.Change{
+				{Criticality: schemadiff.Safe, Coordinate: "Query.a", Message: "Query.a was deprecated"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := schemadiff.Compare(loadSchema(t, tt.oldSchema), loadSchema(t, tt.newSchema))
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestCompare_introspection(t *testing.T) {
+	sdlSchema := loadSchema(t, `
+directive @auth(role: String!) on FIELD_DEFINITION
+type Query { a: String @auth(role: "admin") }
+`)
+
+	// the server lists only the directives of the spec it implements, and none of the prelude of gqlparser
+	result := `{"__schema": {
+		"queryType": {"name": "Query"},
+		"types": [
+			{"kind": "OBJECT", "name": "Query", "interfaces": [], "fields": [
+				{"name": "a", "args": [], "type": {"kind": "SCALAR", "name": "String"}, "isDeprecated": false}
+			]},
+			{"kind": "SCALAR", "name": "String"},
+			{"kind": "SCALAR", "name": "Boolean"}
+		],
+		"directives": [
+			{"name": "auth", "locations": ["FIELD_DEFINITION"], "args": [
+				{"name": "role", "type": {"kind": "NON_NULL", "ofType": {"kind": "SCALAR", "name": "String"}}}
+			]},
+			{"name": "include", "locations": ["FIELD", "FRAGMENT_SPREAD", "INLINE_FRAGMENT"], "args": [
+				{"name": "if", "type": {"kind": "NON_NULL", "ofType": {"kind": "SCALAR", "name": "Boolean"}}}
+			]},
+			{"name": "skip", "locations": ["FIELD", "FRAGMENT_SPREAD", "INLINE_FRAGMENT"], "args": [
+				{"name": "if", "type": {"kind": "NON_NULL", "ofType": {"kind": "SCALAR", "name": "Boolean"}}}
+			]}
+		]
+	}}`
+	var query introspection.Query
+	if err := json.Unmarshal([]byte(result), &query); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := introspection.ParseIntrospectionQuery(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	introspectedSchema, gqlerr := validator.ValidateSchemaDocument(doc)
+	if gqlerr != nil {
+		t.Fatal(gqlerr)
+	}
+
+	if diff := cmp.Diff([]*schemadiff.Change(nil), schemadiff.Compare(sdlSchema, introspectedSchema)); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff([]*schemadiff.Change(nil), schemadiff.Compare(introspectedSchema, sdlSchema)); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestUsage_Affects(t *testing.T) {
+	schema := loadSchema(t, `
+type Query { user(id: ID!): User users(filter: Filter): [User!]! }
+type User { id: ID! name: String color: Color }
+input Filter { name: String }
+enum Color { RED }
+`)
+	query, errs := gqlparser.LoadQuery(schema, `
+query GetUser { user(id: "1") { name } }
+query ListUsers($filter: Filter) { users(filter: $filter) { ...UserColor } }
+fragment UserColor on User { color }
+`)
+	if errs != nil {
+		t.Fatal(errs)
+	}
+
+	tests := []struct {
+		coordinate string
+		want       []string
+	}{
+		{coordinate: "User.name", want: []string{"GetUser"}},
+		{coordinate: "Query.user(id:)", want: []string{"GetUser"}},
+		{coordinate: "Color", want: []string{"ListUsers"}},
+		{coordinate: "Filter.name", want: []string{"ListUsers"}},
+		{coordinate: "User.id", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.coordinate, func(t *testing.T) {
+			change := &schemadiff.Change{Coordinate: tt.coordinate}
+			var got []string
+			for _, operation := range query.Operations {
+				if schemadiff.NewUsage(schema, operation).Affects(change) {
+					got = append(got, operation.Name)
+				}
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package schemadiff
+
+import (
+	"fmt"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Usage is the set of schema coordinates an operation depends on.
+type Usage map[string]bool
+
+// NewUsage collects the coordinates used by the operation, which must be validated against schema.
+func NewUsage(schema *ast.Schema, operation *ast.OperationDefinition) Usage {
+	u := Usage{}
+	if root := rootDefinition(schema, operation.Operation); root != nil {
+		u[root.Name] = true
+	}
+
+	for _, variable := range operation.VariableDefinitions {
+		u.addInputType(schema, variable.Type.Name())
+	}
+	u.addDirectives(operation.Directives)
+	u.addSelectionSet(schema, operation.SelectionSet, map[string]bool{})
+
+	return u
+}
+
+// Affects reports whether the change is about a coordinate the operation depends on.
+func (u Usage) Affects(change *Change) bool {
+	return u[change.Coordinate]
+}
+
+func rootDefinition(schema *ast.Schema, operation ast.Operation) *ast.Definition {
+	switch operation {
+	case ast.Query:
+		return schema.Query
+	case ast.Mutation:
+		return schema.Mutation
+	case ast.Subscription:
+		return schema.Subscription
+	}
+
+	return nil
+}
+
+func (u Usage) addSelectionSet(schema *ast.Schema, selectionSet ast.SelectionSet, visitedFragments map[string]bool) {
+	for _, selection := range selectionSet {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			u.addDirectives(selection.Directives)
+			if selection.Definition == nil || selection.ObjectDefinition == nil {
+				continue
+			}
+
+			coordinate := selection.ObjectDefinition.Name + "." + selection.Name
+			u[selection.ObjectDefinition.Name] = true
+			u[coordinate] = true
+			for _, arg := range selection.Arguments {
+				u[fmt.Sprintf("%s(%s:)", coordinate, arg.Name)] = true
+				if argDef := selection.Definition.Arguments.ForName(arg.Name); argDef != nil {
+					u.addInputType(schema, argDef.Type.Name())
+				}
+			}
+			u[selection.Definition.Type.Name()] = true
+
+			u.addSelectionSet(schema, selection.SelectionSet, visitedFragments)
+		case *ast.InlineFragment:
+			u.addDirectives(selection.Directives)
+			if selection.TypeCondition != "" {
+				u[selection.TypeCondition] = true
+			}
+
+			u.addSelectionSet(schema, selection.SelectionSet, visitedFragments)
+		case *ast.FragmentSpread:
+			u.addDirectives(selection.Directives)
+			if selection.Definition == nil || visitedFragments[selection.Name] {
+				continue
+			}
+			visitedFragments[selection.Name] = true
+			u[selection.Definition.TypeCondition] = true
+
+			u.addSelectionSet(schema, selection.Definition.SelectionSet, visitedFragments)
+		}
+	}
+}
+
+// addInputType adds the type and, for input objects, all of its fields because
+// any of them may be given through variables.
+func (u Usage) addInputType(schema *ast.Schema, name string) {
+	if u[name] {
+		return
+	}
+	u[name] = true
+
+	def := schema.Types[name]
+	if def == nil || def.Kind != ast.InputObject {
+		return
+	}
+	for _, field := range def.Fields {
+		u[name+"."+field.Name] = true
+		u.addInputType(schema, field.Type.Name())
+	}
+}
+
+func (u Usage) addDirectives(directives ast.DirectiveList) {
+	for _, directive := range directives {
+		coordinate := "@" + directive.Name
+		u[coordinate] = true
+		for _, arg := range directive.Arguments {
+			u[fmt.Sprintf("%s(%s:)", coordinate, arg.Name)] = true
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package sdl
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/Yamashou/gqlgenc/internal/unified"
+	"github.com/vektah/gqlparser/v2/ast"
+	"golang.org/x/xerrors"
+)
+
+const (
+	lockHeader     = "# Code generated by github.com/Yamashou/gqlgenc, DO NOT EDIT."
+	lockHashPrefix = "# sha256: "
+)
+
+// Lock is the normalized SDL of a schema and its hash, which is written on generation
+// to detect changes of the remote schema.
+type Lock struct {
+	Hash string
+	SDL  string
+}
+
+func NewLock(schema *ast.Schema) *Lock {
+	s := Format(schema)
+
+	return &Lock{
+		Hash: hash(s),
+		SDL:  s,
+	}
+}
+
+func hash(s string) string {
+	sum := sha256.Sum256([]byte(s))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func ReadLock(filename string) (*Lock, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to read lock file: %w", err)
+	}
+
+	lock, err := parseLock(string(b))
+	if err != nil {
+		return nil, xerrors.Errorf("%s: %w", filename, err)
+	}
+
+	return lock, nil
+}
+
+func parseLock(content string) (*Lock, error) {
+	reader := bufio.NewReader(strings.NewReader(content))
+	var lock Lock
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, xerrors.New("hash is not found")
+		}
+		if strings.HasPrefix(line, lockHashPrefix) {
+			lock.Hash = strings.TrimSpace(strings.TrimPrefix(line, lockHashPrefix))
+
+			break
+		}
+	}
+
+	// skip the blank line between the header and the SDL
+	rest, _ := ioutil.ReadAll(reader)
+	lock.SDL = strings.TrimPrefix(string(rest), "\n")
+
+	if hash(lock.SDL) != lock.Hash {
+		return nil, xerrors.New("hash does not match the content, the lock file may be edited by hand")
+	}
+
+	return &lock, nil
+}
+
+func (l *Lock) String() string {
+	return fmt.Sprintf("%s\n%s%s\n\n%s", lockHeader, lockHashPrefix, l.Hash, l.SDL)
+}
+
+func (l *Lock) Write(filename string) error {
+	if err := ioutil.WriteFile(filename, []byte(l.String()), 0o644); err != nil {
+		return xerrors.Errorf("unable to write lock file: %w", err)
+	}
+
+	return nil
+}
+
+// Diff returns the unified diff of the SDL from l to other, or an empty string if their hashes are equal.
+func (l *Lock) Diff(other *Lock) string {
+	if l.Hash == other.Hash {
+		return ""
+	}
+
+	return unified.Diff("lock", "schema", l.SDL, other.SDL)
+}
@@ -0,0 +1,62 @@
+package sdl_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Yamashou/gqlgenc/sdl"
+	"github.com/google/go-cmp/cmp"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestLock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gqlgenc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	schema := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: `type Query { name: String }`})
+	filename := filepath.Join(dir, "schema.lock")
+	if err := sdl.NewLock(schema).Write(filename); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := sdl.ReadLock(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(sdl.NewLock(schema), got); diff != "" {
+		t.Error(diff)
+	}
+
+	changed := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: `type Query { name: String! }`})
+	diff := got.Diff(sdl.NewLock(changed))
+	if !strings.Contains(diff, "-\tname: String\n+\tname: String!\n") {
+		t.Errorf("unexpected diff:\n%s", diff)
+	}
+}
+
+func TestReadLock_edited(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gqlgenc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	schema := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: `type Query { name: String }`})
+	lock := sdl.NewLock(schema)
+	lock.SDL += "scalar Edited\n"
+	filename := filepath.Join(dir, "schema.lock")
+	if err := lock.Write(filename); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := sdl.ReadLock(filename); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,57 @@
+package sdl
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
+)
+
+var specifiedTypes = map[string]bool{
+	"Int":     true,
+	"Float":   true,
+	"String":  true,
+	"Boolean": true,
+	"ID":      true,
+}
+
+var specifiedDirectives = map[string]bool{
+	"include":     true,
+	"skip":        true,
+	"deprecated":  true,
+	"specifiedBy": true,
+	"oneOf":       true,
+	// not in the spec yet, but gqlparser adds it to every schema
+	"defer": true,
+}
+
+// Format prints the schema as SDL, sorted by name and without the types and directives defined by the GraphQL spec.
+// The schema may come either from SDL files or from an introspection query.
+func Format(schema *ast.Schema) string {
+	var buf bytes.Buffer
+	formatter.NewFormatter(&buf).FormatSchema(normalize(schema))
+
+	return buf.String()
+}
+
+// The formatter skips built-in definitions, but a schema built from an introspection query marks
+// every definition as built-in and has no position for directives, so mark them again by name.
+func normalize(schema *ast.Schema) *ast.Schema {
+	normalized := *schema
+	normalized.Types = make(map[string]*ast.Definition, len(schema.Types))
+	for name, def := range schema.Types {
+		d := *def
+		d.BuiltIn = specifiedTypes[name] || strings.HasPrefix(name, "__")
+		normalized.Types[name] = &d
+	}
+
+	normalized.Directives = make(map[string]*ast.DirectiveDefinition, len(schema.Directives))
+	for name, def := range schema.Directives {
+		d := *def
+		d.Position = &ast.Position{Src: &ast.Source{BuiltIn: specifiedDirectives[name]}}
+		normalized.Directives[name] = &d
+	}
+
+	return &normalized
+}
@@ -0,0 +1,39 @@
+package sdl_test
+
+import (
+	"testing"
+
+	"github.com/Yamashou/gqlgenc/sdl"
+	"github.com/google/go-cmp/cmp"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestFormat(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+"A user"
+type User {
+  name: String!
+  nickname: String @deprecated(reason: "use name")
+}
+
+type Query {
+  user: User
+}
+`})
+
+	want := `type Query {
+	user: User
+}
+"""
+A user
+"""
+type User {
+	name: String!
+	nickname: String @deprecated(reason: "use name")
+}
+`
+	if diff := cmp.Diff(want, sdl.Format(schema)); diff != "" {
+		t.Error(diff)
+	}
+}
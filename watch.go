@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/99designs/gqlgen/api"
+	"github.com/Yamashou/gqlgenc/clientgen"
+	"github.com/Yamashou/gqlgenc/config"
+	"github.com/Yamashou/gqlgenc/generator"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+const (
+	// watchInterval is how often the watched files are checked for changes
+	watchInterval = 300 * time.Millisecond
+	// watchDebounce is how long the files must stay unchanged before regenerating,
+	// so that an editor saving several files causes a single generation
+	watchDebounce = 200 * time.Millisecond
+)
+
+// fileState is the modification time and size of a watched file.
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// watchedFiles are the states of the config file, the query files and the local schema files.
+type watchedFiles struct {
+	config  map[string]fileState
+	schema  map[string]fileState
+	queries map[string]fileState
+}
+
+// runWatch generates the code, and generates it again whenever the watched files change until interrupted.
+// The schema is kept in memory while only query files change.
+func runWatch(ctx context.Context, diagnostics string) int {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	go func() {
+		<-interrupt
+		cancel()
+	}()
+
+	files := statWatchedFiles()
+	schema := watchGenerate(ctx, nil, diagnostics)
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return 0
+		case <-ticker.C:
+		}
+
+		changed := statWatchedFiles()
+		if changed.equal(files) {
+			continue
+		}
+		// wait until the files stop changing
+		for {
+			time.Sleep(watchDebounce)
+			next := statWatchedFiles()
+			if next.equal(changed) {
+				break
+			}
+			changed = next
+		}
+
+		if !statesEqual(changed.config, files.config) || !statesEqual(changed.schema, files.schema) {
+			schema = nil
+		}
+		files = changed
+		schema = watchGenerate(ctx, schema, diagnostics)
+	}
+}
+
+// watchGenerate generates the code with the schema if not nil, and returns the schema it used.
+// Errors are printed rather than returned to keep watching.
+func watchGenerate(ctx context.Context, schema *ast.Schema, diagnostics string) *ast.Schema {
+	start := time.Now()
+
+	cfg, err := config.LoadConfig(configFilename)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%+v\n", err.Error())
+
+		return nil
+	}
+	cfg.GQLConfig.Schema = schema
+	cfg.Warn = printWarning
+
+	clientPlugin := generator.NewClientPlugin(cfg)
+	if err := generator.Generate(ctx, cfg, api.AddPlugin(clientPlugin)); err != nil {
+		printGenerateError(err, diagnostics)
+
+		return cfg.GQLConfig.Schema
+	}
+	fmt.Fprintf(os.Stderr, "generated in %s, watching for changes\n", time.Since(start).Round(time.Millisecond))
+
+	return cfg.GQLConfig.Schema
+}
+
+func statWatchedFiles() *watchedFiles {
+	files := &watchedFiles{
+		config: statFiles([]string{configFilename}),
+	}
+
+	// the globs come from the config, which may be broken while editing
+	cfg, err := config.LoadConfig(configFilename)
+	if err != nil {
+		return files
+	}
+	files.schema = statGlobs(cfg.SchemaFilename)
+	files.queries = statGlobs(cfg.Query)
+	// a change of the template needs no schema reload either, as with query files
+	if cfg.Client.Template != "" {
+		if files.queries == nil {
+			files.queries = make(map[string]fileState)
+		}
+		for filename, state := range statFiles([]string{cfg.Client.Template}) {
+			files.queries[filename] = state
+		}
+	}
+
+	return files
+}
+
+func statGlobs(globs []string) map[string]fileState {
+	filenames, err := clientgen.QueryFilenames(globs)
+	if err != nil {
+		return nil
+	}
+
+	return statFiles(filenames)
+}
+
+func statFiles(filenames []string) map[string]fileState {
+	states := make(map[string]fileState, len(filenames))
+	for _, filename := range filenames {
+		info, err := os.Stat(filename)
+		if err != nil {
+			continue
+		}
+		states[filename] = fileState{modTime: info.ModTime(), size: info.Size()}
+	}
+
+	return states
+}
+
+func (w *watchedFiles) equal(other *watchedFiles) bool {
+	return statesEqual(w.config, other.config) && statesEqual(w.schema, other.schema) && statesEqual(w.queries, other.queries)
+}
+
+func statesEqual(a, b map[string]fileState) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for filename, state := range a {
+		other, ok := b[filename]
+		if !ok || !state.modTime.Equal(other.modTime) || state.size != other.size {
+			return false
+		}
+	}
+
+	return true
+}